@@ -1,34 +1,209 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/TheChessDev/lazydynamo/internals/tools"
 	"github.com/TheChessDev/lazydynamo/tui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+const (
+	maxLogSize    = 5 * 1024 * 1024 // Rotate persistent logs once they reach 5 MB
+	maxLogBackups = 3
+
+	// Longest wait on exit for cancelled scans to return
+	shutdownTimeout = 2 * time.Second
+)
+
 func main() {
+	// Exit only once start returned, so its deferred cleanup has run
+	os.Exit(start())
+}
+
+// start reads the configuration, sets up logging and runs the program,
+// returning the process exit code
+func start() int {
+	config, err := tools.LoadConfig(lazydynamo.ConfigFilePath)
+	if err != nil {
+		fmt.Println("Couldn't read the config file:", err)
+		return 1
+	}
+
+	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
+		config.Profile = profile
+	}
+	flag.StringVar(&config.Profile, "profile", config.Profile, "AWS profile to use (env AWS_PROFILE)")
+	persistLog := flag.Bool("log", os.Getenv("LAZYDYNAMO_LOG") != "", "keep debug logs in "+filepath.Join(lazydynamo.CacheDir, "lazydynamo.log"))
+	flag.StringVar(&config.RoleArn, "role-arn", config.RoleArn, "ARN of an IAM role to assume")
+	flag.StringVar(&config.ExternalID, "external-id", config.ExternalID, "external ID to pass when assuming the role")
+	flag.StringVar(&config.RoleSessionName, "role-session-name", config.RoleSessionName, "session name to use when assuming the role")
+	flag.BoolVar(&config.ReadOnly, "read-only", config.ReadOnly, "disable all actions that write to DynamoDB")
+	if timeout := os.Getenv("LAZYDYNAMO_SCAN_TIMEOUT"); timeout != "" {
+		config.ScanTimeout = timeout
+	}
+	flag.StringVar(&config.ScanTimeout, "scan-timeout", config.ScanTimeout, "how long a table scan may run, e.g. 5m (env LAZYDYNAMO_SCAN_TIMEOUT)")
+	if limit := os.Getenv("LAZYDYNAMO_SCAN_PAGE_LIMIT"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			fmt.Println("LAZYDYNAMO_SCAN_PAGE_LIMIT must be a number:", err)
+			return 1
+		}
+		config.ScanPageLimit = value
+	}
+	flag.IntVar(&config.ScanPageLimit, "scan-page-limit", config.ScanPageLimit, "items per scan request, 1-1000 (env LAZYDYNAMO_SCAN_PAGE_LIMIT)")
+	if segments := os.Getenv("LAZYDYNAMO_SCAN_SEGMENTS"); segments != "" {
+		value, err := strconv.Atoi(segments)
+		if err != nil {
+			fmt.Println("LAZYDYNAMO_SCAN_SEGMENTS must be a number:", err)
+			return 1
+		}
+		config.ScanSegments = value
+	}
+	flag.IntVar(&config.ScanSegments, "scan-segments", config.ScanSegments, "most segments a scan reads in parallel (env LAZYDYNAMO_SCAN_SEGMENTS)")
+	flag.BoolVar(&config.UncompressedCache, "uncompressed-cache", config.UncompressedCache, "write table data caches as plain JSON instead of gzip")
+	flag.IntVar(&config.SampleSize, "sample-size", config.SampleSize, fmt.Sprintf("items a scan in sample mode stops after (default %d)", lazydynamo.DefaultSampleSize))
+	if ratio := os.Getenv("LAZYDYNAMO_PANE_RATIO"); ratio != "" {
+		value, err := strconv.ParseFloat(ratio, 64)
+		if err != nil {
+			fmt.Println("LAZYDYNAMO_PANE_RATIO must be a number:", err)
+			return 1
+		}
+		config.PaneRatio = value
+	}
+	flag.Float64Var(&config.PaneRatio, "pane-ratio", config.PaneRatio, "share of the width the collections pane takes, 0.15-0.6 (env LAZYDYNAMO_PANE_RATIO)")
+	flag.StringVar(&config.Theme, "theme", config.Theme, "color theme: "+strings.Join(tools.ThemeNames(), ", "))
+	flag.StringVar(&config.ThemeFile, "theme-file", config.ThemeFile, "JSON file of theme colors, applied on top of the theme")
+	flag.StringVar(&config.Table, "table", config.Table, "table to open and scan on startup")
+	flag.StringVar(&config.Mode, "mode", config.Mode, "mode to start in: collections or view")
+	regions := flag.String("regions", strings.Join(config.Regions, ","), "comma separated regions whose tables are listed together")
+	flag.Parse()
+
+	if config.ScanPageLimit != 0 && (config.ScanPageLimit < lazydynamo.MinScanPageLimit || config.ScanPageLimit > lazydynamo.MaxScanPageLimit) {
+		fmt.Printf("The scan page limit must be between %d and %d, got %d\n", lazydynamo.MinScanPageLimit, lazydynamo.MaxScanPageLimit, config.ScanPageLimit)
+		return 1
+	}
+
+	if config.ScanSegments < 0 {
+		fmt.Println("The scan segments must not be negative, got", config.ScanSegments)
+		return 1
+	}
+
+	if _, err := tools.LoadTheme(config.Theme, config.ThemeFile); err != nil {
+		fmt.Println("Couldn't load the theme:", err)
+		return 1
+	}
+
+	if config.SampleSize < 0 {
+		fmt.Println("The sample size must not be negative, got", config.SampleSize)
+		return 1
+	}
+
+	if !lazydynamo.ValidStartMode(config.Mode) {
+		fmt.Printf("The mode must be %s or %s, got %q\n", lazydynamo.StartModeCollections, lazydynamo.StartModeView, config.Mode)
+		return 1
+	}
+
+	if config.PaneRatio != 0 && (config.PaneRatio < lazydynamo.MinPaneRatio || config.PaneRatio > lazydynamo.MaxPaneRatio) {
+		fmt.Printf("The pane ratio must be between %.2f and %.2f, got %.2f\n", lazydynamo.MinPaneRatio, lazydynamo.MaxPaneRatio, config.PaneRatio)
+		return 1
+	}
+
+	config.Regions = nil
+	for _, region := range strings.Split(*regions, ",") {
+		if region = strings.TrimSpace(region); region != "" {
+			config.Regions = append(config.Regions, region)
+		}
+	}
+
+	if *persistLog {
+		logPath, err := setupPersistentLog()
+		if err != nil {
+			fmt.Println("Couldn't set up the log file:", err)
+			return 1
+		}
+
+		f, err := tools.OpenRotatingLog(logPath, maxLogSize, maxLogBackups)
+		if err != nil {
+			fmt.Println("Couldn't open the log file:", err)
+			return 1
+		}
+		defer func() {
+			// Work that outlived the wait in run must not log to a closed file
+			log.SetOutput(io.Discard)
+			f.Close()
+		}()
+		log.SetOutput(f)
+		log.SetPrefix("lazydynamo ")
+
+		lazydynamo.LogFilePath = logPath
+		return run(*config)
+	}
+
 	var f *os.File
 
 	// Create a temporary file for logging in the OS's temp directory
 	tempFile, err := os.CreateTemp("", "lazydynamo-debug-*.log")
 	if err != nil {
 		fmt.Println("Couldn't create a temporary log file:", err)
-		os.Exit(1)
+		return 1
 	}
 	f = tempFile
 
 	// Set up logging to the temporary file
 	tea.LogToFile(f.Name(), "lazydynamo")
+	lazydynamo.LogFilePath = f.Name()
 
 	defer func() {
+		log.SetOutput(io.Discard)
 		f.Close()           // Close the file
 		os.Remove(f.Name()) // Remove the file when done (if desired)
 	}()
 
-	if _, err := tea.NewProgram(lazydynamo.New(), tea.WithAltScreen(), tea.WithMouseCellMotion()).Run(); err != nil {
+	return run(*config)
+}
+
+func run(config tools.Config) int {
+	if lazydynamo.FirstLaunch() {
+		err := lazydynamo.RunSetup(&config)
+		if errors.Is(err, lazydynamo.ErrSetupQuit) {
+			return 0
+		}
+		if err != nil {
+			fmt.Println("Couldn't save the setup:", err)
+			return 1
+		}
+	}
+
+	_, err := tea.NewProgram(lazydynamo.New(config), tea.WithAltScreen(), tea.WithMouseCellMotion()).Run()
+
+	// Quitting cancels the scans still running; give them a moment to
+	// return before the log file goes away
+	if !lazydynamo.StopBackgroundWork(shutdownTimeout) {
+		log.Println("Scans still running on exit")
+	}
+
+	if err != nil {
 		fmt.Println("Error running program:", err)
-		os.Exit(1)
+		return 1
 	}
+	return 0
+}
+
+// setupPersistentLog prepares the directory of the log file kept across runs
+func setupPersistentLog() (string, error) {
+	if err := os.MkdirAll(lazydynamo.CacheDir, 0755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(lazydynamo.CacheDir, "lazydynamo.log"), nil
 }
@@ -0,0 +1,200 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ConfirmResultMsg reports the user's answer to a ConfirmDialog. ID is the
+// identifier the dialog was opened with so the caller knows which action was
+// being confirmed.
+type ConfirmResultMsg struct {
+	ID        string
+	Confirmed bool
+}
+
+// ConfirmCopyMsg asks the caller to copy the detail of a ConfirmDialog, sent
+// when the user presses c while it is open
+type ConfirmCopyMsg struct {
+	ID   string
+	Text string
+}
+
+// maxDetailLines bounds how much of the detail is shown; it can still be
+// copied in full
+const maxDetailLines = 20
+
+// ConfirmDialog is a yes/no modal. While active it captures all key input
+// until the user answers or dismisses it. Opened with OpenTyped it instead
+// asks the user to type a phrase, for actions too destructive for a y/n.
+type ConfirmDialog struct {
+	BoxStyle  lipgloss.Style
+	HintStyle lipgloss.Style
+
+	id       string
+	message  string
+	detail   string // text shown below the message, e.g. a request preview
+	active   bool
+	expected string // phrase that must be typed to confirm, if any
+	input    textinput.Model
+}
+
+func NewConfirmDialog(color lipgloss.Color) ConfirmDialog {
+	return ConfirmDialog{
+		BoxStyle: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color).
+			Align(lipgloss.Center).
+			Padding(1, 2),
+		HintStyle: lipgloss.NewStyle().Faint(true),
+	}
+}
+
+// Open shows the dialog with the given message
+func (d ConfirmDialog) Open(id, message string) ConfirmDialog {
+	d.id = id
+	d.message = message
+	d.detail = ""
+	d.active = true
+
+	return d
+}
+
+// OpenWithDetail shows the dialog with a detail below the message, which the
+// user can copy with c
+func (d ConfirmDialog) OpenWithDetail(id, message, detail string) ConfirmDialog {
+	return d.Open(id, message).WithDetail(detail)
+}
+
+// WithDetail adds a detail below the message of an opened dialog. In a dialog
+// opened with OpenTyped it is copied with ctrl+y, since c is typed.
+func (d ConfirmDialog) WithDetail(detail string) ConfirmDialog {
+	d.detail = detail
+
+	return d
+}
+
+// OpenTyped shows the dialog and only confirms once expected has been typed
+// exactly
+func (d ConfirmDialog) OpenTyped(id, message, expected string) ConfirmDialog {
+	d = d.Open(id, message)
+	d.expected = expected
+	d.input = textinput.New()
+	d.input.Prompt = "> "
+	d.input.Focus()
+
+	return d
+}
+
+func (d ConfirmDialog) Active() bool {
+	return d.active
+}
+
+// Update handles key input while the dialog is open. Only y confirms; no is
+// the default, so n, esc and enter decline. Both close the dialog and send a
+// ConfirmResultMsg.
+func (d ConfirmDialog) Update(msg tea.Msg) (ConfirmDialog, tea.Cmd) {
+	if !d.active {
+		return d, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return d, nil
+	}
+
+	if d.expected != "" {
+		return d.updateTyped(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "y", "Y":
+		d.active = false
+		return d, d.result(true)
+	case "n", "N", "esc", "enter":
+		d.active = false
+		return d, d.result(false)
+	case "c":
+		return d, d.copyDetail()
+	}
+
+	return d, nil
+}
+
+// updateTyped handles key input for a dialog opened with OpenTyped. enter
+// confirms when the input matches, esc declines.
+func (d ConfirmDialog) updateTyped(msg tea.KeyMsg) (ConfirmDialog, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlY:
+		return d, d.copyDetail()
+	case tea.KeyEnter:
+		if d.input.Value() != d.expected {
+			return d, nil
+		}
+		d.active = false
+		d.expected = ""
+		return d, d.result(true)
+	case tea.KeyEsc:
+		d.active = false
+		d.expected = ""
+		return d, d.result(false)
+	}
+
+	var cmd tea.Cmd
+	d.input, cmd = d.input.Update(msg)
+	return d, cmd
+}
+
+// copyDetail asks the caller to copy the detail, if there is one
+func (d ConfirmDialog) copyDetail() tea.Cmd {
+	if d.detail == "" {
+		return nil
+	}
+	id, text := d.id, d.detail
+	return func() tea.Msg { return ConfirmCopyMsg{ID: id, Text: text} }
+}
+
+func (d ConfirmDialog) result(confirmed bool) tea.Cmd {
+	id := d.id
+	return func() tea.Msg {
+		return ConfirmResultMsg{ID: id, Confirmed: confirmed}
+	}
+}
+
+// View renders the dialog centered in an area of the given size
+func (d ConfirmDialog) View(width, height int) string {
+	hint := "y: yes • N/esc/enter: no"
+	if d.detail != "" {
+		hint += " • c: copy"
+	}
+	if d.expected != "" {
+		hint = "type " + d.expected + " and press enter • esc: cancel"
+		if d.detail != "" {
+			hint += " • ctrl+y: copy"
+		}
+	}
+
+	content := d.message
+	if d.detail != "" {
+		content += "\n\n" + lipgloss.NewStyle().Align(lipgloss.Left).Render(truncateLines(d.detail, maxDetailLines))
+	}
+	if d.expected != "" {
+		content += "\n\n" + d.input.View()
+	}
+	content += "\n\n" + d.HintStyle.Render(hint)
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, d.BoxStyle.Render(content))
+}
+
+// truncateLines keeps the first max lines of text
+func truncateLines(text string, max int) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) <= max {
+		return text
+	}
+	return strings.Join(lines[:max], "\n") + fmt.Sprintf("\n… %d more lines", len(lines)-max)
+}
@@ -0,0 +1,125 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HelpSection is a titled group of key bindings shown in the HelpOverlay
+type HelpSection struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// HelpOverlay is a full-screen, scrollable list of key bindings grouped by
+// section. While active it captures all key input until it is closed with
+// ?, esc or q.
+type HelpOverlay struct {
+	BoxStyle   lipgloss.Style
+	TitleStyle lipgloss.Style
+	KeyStyle   lipgloss.Style
+	HintStyle  lipgloss.Style
+
+	sections []HelpSection
+	viewport viewport.Model
+	active   bool
+}
+
+func NewHelpOverlay(color lipgloss.Color) HelpOverlay {
+	return HelpOverlay{
+		BoxStyle: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color).
+			Padding(0, 2),
+		TitleStyle: lipgloss.NewStyle().Bold(true).Foreground(color),
+		KeyStyle:   lipgloss.NewStyle().Bold(true),
+		HintStyle:  lipgloss.NewStyle().Faint(true),
+	}
+}
+
+// Open shows the overlay with the given sections
+func (h HelpOverlay) Open(sections []HelpSection) HelpOverlay {
+	h.sections = sections
+	h.active = true
+	h.viewport.SetContent(h.content())
+	h.viewport.GotoTop()
+
+	return h
+}
+
+// SetSize fits the overlay to a screen of the given size
+func (h HelpOverlay) SetSize(width, height int) HelpOverlay {
+	h.viewport = viewport.New(max(width-8, 10), max(height-5, 3))
+	h.viewport.SetContent(h.content())
+
+	return h
+}
+
+func (h HelpOverlay) Active() bool {
+	return h.active
+}
+
+// Update handles key input while the overlay is open
+func (h HelpOverlay) Update(msg tea.Msg) (HelpOverlay, tea.Cmd) {
+	if !h.active {
+		return h, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "?", "esc", "q":
+			h.active = false
+			return h, nil
+		case "g", "home":
+			h.viewport.GotoTop()
+			return h, nil
+		case "G", "end":
+			h.viewport.GotoBottom()
+			return h, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	h.viewport, cmd = h.viewport.Update(msg)
+	return h, cmd
+}
+
+// View renders the overlay centered in an area of the given size
+func (h HelpOverlay) View(width, height int) string {
+	hint := h.HintStyle.Render("↑/↓ scroll • ?/esc/q close")
+	box := h.BoxStyle.Width(width - 4).Render(h.viewport.View() + "\n" + hint)
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// content lays out every section with its keys aligned in a column
+func (h HelpOverlay) content() string {
+	keyWidth := 0
+	for _, section := range h.sections {
+		for _, binding := range section.Bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(binding.Help().Key))
+		}
+	}
+
+	var b strings.Builder
+	for i, section := range h.sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(h.TitleStyle.Render(section.Title) + "\n")
+		for _, binding := range section.Bindings {
+			if !binding.Enabled() {
+				continue
+			}
+			keys := fmt.Sprintf("%-*s", keyWidth, binding.Help().Key)
+			b.WriteString("  " + h.KeyStyle.Render(keys) + "  " + binding.Help().Desc + "\n")
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AWSRegions lists the regions DynamoDB is available in, offered when picking
+// a region
+var AWSRegions = []string{
+	"us-east-1", "us-east-2", "us-west-1", "us-west-2",
+	"af-south-1",
+	"ap-east-1", "ap-south-1", "ap-south-2", "ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+	"ap-southeast-1", "ap-southeast-2", "ap-southeast-3", "ap-southeast-4",
+	"ca-central-1", "ca-west-1",
+	"eu-central-1", "eu-central-2", "eu-west-1", "eu-west-2", "eu-west-3",
+	"eu-north-1", "eu-south-1", "eu-south-2",
+	"il-central-1",
+	"me-central-1", "me-south-1",
+	"sa-east-1",
+}
+
+// AWSProfile is a profile of the shared AWS config or credentials file
+type AWSProfile struct {
+	Name   string
+	Region string // region the profile sets, if any
+}
+
+// ListAWSProfiles reads the profiles of the shared AWS config and credentials
+// files, honoring AWS_CONFIG_FILE and AWS_SHARED_CREDENTIALS_FILE. Missing
+// files are skipped; the profiles are sorted with "default" first.
+func ListAWSProfiles() ([]AWSProfile, error) {
+	home, _ := os.UserHomeDir()
+	configFile := os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = filepath.Join(home, ".aws", "config")
+	}
+	credentialsFile := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = filepath.Join(home, ".aws", "credentials")
+	}
+
+	profiles := make(map[string]*AWSProfile)
+	// Only the config file names its profiles "profile <name>"
+	for _, file := range []struct {
+		path   string
+		prefix string
+	}{{configFile, "profile "}, {credentialsFile, ""}} {
+		if err := readProfiles(file.path, file.prefix, profiles); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	list := make([]AWSProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		list = append(list, *profile)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if (list[i].Name == "default") != (list[j].Name == "default") {
+			return list[i].Name == "default"
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// readProfiles adds the sections of an INI style AWS file to profiles
+func readProfiles(path, prefix string, profiles map[string]*AWSProfile) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var current *AWSProfile
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(strings.Trim(line, "[]"))
+			current = nil
+			if name != "default" {
+				// Other sections, e.g. "sso-session name", aren't profiles
+				var ok bool
+				name, ok = strings.CutPrefix(name, prefix)
+				name = strings.TrimSpace(name)
+				if !ok || strings.Contains(name, " ") {
+					continue
+				}
+			}
+			if profiles[name] == nil {
+				profiles[name] = &AWSProfile{Name: name}
+			}
+			current = profiles[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if ok && current != nil && strings.TrimSpace(key) == "region" {
+			current.Region = strings.TrimSpace(value)
+		}
+	}
+	return scanner.Err()
+}
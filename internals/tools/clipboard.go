@@ -0,0 +1,9 @@
+package tools
+
+import "github.com/atotto/clipboard"
+
+// CopyToClipboard puts text on the system clipboard. It fails when no
+// clipboard tool is available, e.g. xclip or xsel on Linux.
+func CopyToClipboard(text string) error {
+	return clipboard.WriteAll(text)
+}
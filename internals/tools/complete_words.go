@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CompleteLastWord returns value completed with each word that starts with
+// the word being typed at its end, e.g. `status = "x" and cr` with the word
+// "createdAt" gives `status = "x" and createdAt`
+func CompleteLastWord(value string, words []string) []string {
+	head := value[:len(value)-len(LastWord(value))]
+
+	var completions []string
+	for _, word := range MatchWords(LastWord(value), words) {
+		completions = append(completions, head+word)
+	}
+	return completions
+}
+
+// MatchWords returns the words starting with prefix, ignoring case. An empty
+// prefix matches every word.
+func MatchWords(prefix string, words []string) []string {
+	prefix = strings.ToLower(prefix)
+
+	var matches []string
+	for _, word := range words {
+		if strings.HasPrefix(strings.ToLower(word), prefix) {
+			matches = append(matches, word)
+		}
+	}
+	return matches
+}
+
+// LastWord returns the word being typed at the end of value: the letters,
+// digits and underscores after the last other character
+func LastWord(value string) string {
+	return value[strings.LastIndexFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})+1:]
+}
@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Config holds the user's settings. It is read from a JSON file and command
+// line flags take precedence over it.
+type Config struct {
+	// Profile names the AWS profile to use. Empty means the SDK's default.
+	Profile string `json:"profile,omitempty"`
+	// RoleArn is an IAM role assumed through STS for cross-account access
+	RoleArn string `json:"roleArn,omitempty"`
+	// ExternalID is passed along when assuming RoleArn, if the role requires one
+	ExternalID string `json:"externalId,omitempty"`
+	// RoleSessionName names the assumed-role session
+	RoleSessionName string `json:"roleSessionName,omitempty"`
+	// ReadOnly disables every action that writes to DynamoDB
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// ScanTimeout limits how long a table scan may run, e.g. "5m" or "300"
+	// (seconds). Empty means the default.
+	ScanTimeout string `json:"scanTimeout,omitempty"`
+	// ScanPageLimit is how many items each scan request asks for, 1 to 1000.
+	// Zero picks a limit from the table's average item size.
+	ScanPageLimit int `json:"scanPageLimit,omitempty"`
+	// ScanSegments caps how many segments a scan reads in parallel. Zero
+	// leaves it at half the CPU cores.
+	ScanSegments int `json:"scanSegments,omitempty"`
+	// SampleSize is how many items a scan in sample mode stops after. Zero
+	// means 500.
+	SampleSize int `json:"sampleSize,omitempty"`
+	// FlattenDepth is how many levels of nested attributes the flattened grid
+	// expands into columns. Zero means 3.
+	FlattenDepth int `json:"flattenDepth,omitempty"`
+	// UncompressedCache writes table data caches as plain JSON rather than
+	// gzip compressed, e.g. to read them with other tools
+	UncompressedCache bool `json:"uncompressedCache,omitempty"`
+	// PaneRatio is the share of the width the collections pane takes, 0.15
+	// to 0.6. Zero means 0.3.
+	PaneRatio float64 `json:"paneRatio,omitempty"`
+	// Regions lists the regions whose tables are shown at startup. With more
+	// than one, the tables of all of them are listed together.
+	Regions []string `json:"regions,omitempty"`
+	// Theme names a built-in color theme: dark, light or solarized. Empty
+	// means dark.
+	Theme string `json:"theme,omitempty"`
+	// ThemeFile is a JSON file of theme colors, applied on top of Theme
+	ThemeFile string `json:"themeFile,omitempty"`
+	// Spinner names the loading spinner style, e.g. "line" or "dot". Empty
+	// means "line".
+	Spinner string `json:"spinner,omitempty"`
+	// SpinnerColor is the loading spinner's color, an ANSI number such as
+	// "10" or a hex color such as "#7aa2f7"
+	SpinnerColor string `json:"spinnerColor,omitempty"`
+	// Table is opened and scanned on startup, given by name or, with several
+	// regions, as "region/table"
+	Table string `json:"table,omitempty"`
+	// Mode is the mode the app starts in: "collections" or "view". Empty
+	// means "collections".
+	Mode string `json:"mode,omitempty"`
+	// HideRegionPane collapses the AWS region pane, leaving its height to
+	// the collections list
+	HideRegionPane bool `json:"hideRegionPane,omitempty"`
+	// TableTags color tables whose names match a pattern, e.g. to tell
+	// production tables apart
+	TableTags []TableTag `json:"tableTags,omitempty"`
+	// SessionHistory keeps what was entered in the prompts for the session
+	// only, rather than saving it in the cache directory
+	SessionHistory bool `json:"sessionHistory,omitempty"`
+	// References lead from attributes holding the key of an item in another
+	// table to that item, e.g. from userId to the users table
+	References []Reference `json:"references,omitempty"`
+}
+
+// TableTag colors the tables matching Pattern, a glob such as "prod-*", and
+// optionally labels them, e.g. "PROD"
+type TableTag struct {
+	Pattern string `json:"pattern"`
+	Color   string `json:"color"`
+	Label   string `json:"label,omitempty"`
+}
+
+// Reference maps Attribute of the tables matching Table, a glob such as
+// "orders-*", to the Target table whose partition key holds its values. With
+// Index, the values are looked up in that index of Target instead.
+type Reference struct {
+	Table     string `json:"table,omitempty"` // empty for every table
+	Attribute string `json:"attribute"`
+	Target    string `json:"target"`
+	Index     string `json:"index,omitempty"`
+}
+
+// MatchReference returns the first reference of the attribute in the table
+func MatchReference(references []Reference, table, attribute string) (Reference, bool) {
+	for _, reference := range references {
+		if reference.Attribute != attribute {
+			continue
+		}
+		if ok, err := path.Match(reference.Table, table); reference.Table == "" || err == nil && ok {
+			return reference, true
+		}
+	}
+	return Reference{}, false
+}
+
+// MatchTableTag returns the first tag whose pattern matches the table name
+func MatchTableTag(tags []TableTag, table string) (TableTag, bool) {
+	for _, tag := range tags {
+		if ok, err := path.Match(tag.Pattern, table); err == nil && ok {
+			return tag, true
+		}
+	}
+	return TableTag{}, false
+}
+
+// ParseTimeout reads a timeout written either as a Go duration ("90s", "5m")
+// or as a plain number of seconds
+func ParseTimeout(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		value = strconv.Itoa(seconds) + "s"
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive, got %q", value)
+	}
+
+	return timeout, nil
+}
+
+// LoadConfig reads the config file at path. A missing file isn't an error and
+// results in an empty config.
+func LoadConfig(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var config Config
+	if err := json.NewDecoder(file).Decode(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// UpdateConfig changes the config file at path in place, keeping settings
+// that were only given as flags out of it
+func UpdateConfig(path string, update func(*Config)) error {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	update(config)
+	return SaveConfig(config, path)
+}
+
+// SaveConfig writes the config to path, creating its directory if needed
+func SaveConfig(config *Config, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
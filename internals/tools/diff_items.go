@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"reflect"
+	"sort"
+)
+
+// DiffKind tells how an attribute differs between two items
+type DiffKind int
+
+const (
+	// DiffAdded attributes are only in the second item
+	DiffAdded DiffKind = iota
+	// DiffRemoved attributes are only in the first item
+	DiffRemoved
+	// DiffChanged attributes are in both items with different values
+	DiffChanged
+)
+
+// FieldDiff is one attribute that differs between two items. Path is dotted
+// for attributes of nested maps, e.g. "address.city".
+type FieldDiff struct {
+	Path string
+	Kind DiffKind
+	Old  interface{} // value in the first item, nil when added
+	New  interface{} // value in the second item, nil when removed
+}
+
+// DiffItems compares two items attribute by attribute and returns the
+// differences ordered by path. Nested maps are compared attribute by
+// attribute too; lists and other values are compared whole.
+func DiffItems(a, b map[string]interface{}) []FieldDiff {
+	var diffs []FieldDiff
+	diffMaps("", a, b, &diffs)
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs
+}
+
+func diffMaps(prefix string, a, b map[string]interface{}, diffs *[]FieldDiff) {
+	for name, oldValue := range a {
+		path := prefix + name
+		newValue, ok := b[name]
+		if !ok {
+			*diffs = append(*diffs, FieldDiff{Path: path, Kind: DiffRemoved, Old: oldValue})
+			continue
+		}
+
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			diffMaps(path+".", oldMap, newMap, diffs)
+			continue
+		}
+
+		if !reflect.DeepEqual(oldValue, newValue) {
+			*diffs = append(*diffs, FieldDiff{Path: path, Kind: DiffChanged, Old: oldValue, New: newValue})
+		}
+	}
+
+	for name, newValue := range b {
+		if _, ok := a[name]; !ok {
+			*diffs = append(*diffs, FieldDiff{Path: prefix + name, Kind: DiffAdded, New: newValue})
+		}
+	}
+}
@@ -1,17 +1,58 @@
 package tools
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
+// binaryPreviewLength is how many base64 characters of a binary value are shown
+// in its display form
+const binaryPreviewLength = 16
+
+// ConversionOptions controls how DynamoDB attribute values are converted
+type ConversionOptions struct {
+	// DisplayBinary renders binary values as labeled, shortened base64 strings
+	// such as "<binary 128 bytes: SGVsbG8...>". The result is meant for
+	// display only and can't be converted back into the original bytes.
+	DisplayBinary bool
+	// FullBinary names top-level attributes, such as the table's keys, whose
+	// binary value is shown whole in the display form, so it can be read back
+	// with ParseBinaryValue
+	FullBinary map[string]bool
+}
+
 // Converts DynamoDB item to a Go map for JSON encoding
 func DynamoItemToMap(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+	return DynamoItemToMapWithOptions(item, ConversionOptions{})
+}
+
+// Converts DynamoDB item to a Go map meant for showing in the UI
+func DynamoItemToDisplayMap(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+	return DynamoItemToMapWithOptions(item, ConversionOptions{DisplayBinary: true})
+}
+
+// DynamoItemToRowMap converts a DynamoDB item like DynamoItemToDisplayMap,
+// except that binary key attributes are shown whole so the item's key can be
+// built from the row again
+func DynamoItemToRowMap(item map[string]types.AttributeValue, keys map[string]bool) (map[string]interface{}, error) {
+	return DynamoItemToMapWithOptions(item, ConversionOptions{DisplayBinary: true, FullBinary: keys})
+}
+
+// Converts DynamoDB item to a Go map using the given conversion options
+func DynamoItemToMapWithOptions(item map[string]types.AttributeValue, opts ConversionOptions) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 	for key, value := range item {
+		if b, ok := value.(*types.AttributeValueMemberB); ok && opts.DisplayBinary && opts.FullBinary[key] {
+			result[key] = fmt.Sprintf("<binary %d bytes: %s>", len(b.Value), base64.StdEncoding.EncodeToString(b.Value))
+			continue
+		}
+
 		var err error
-		result[key], err = attributeValueToInterface(value)
+		result[key], err = attributeValueToInterface(value, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -20,22 +61,26 @@ func DynamoItemToMap(item map[string]types.AttributeValue) (map[string]interface
 }
 
 // Converts a DynamoDB AttributeValue to an interface{} for JSON encoding
-func attributeValueToInterface(av types.AttributeValue) (interface{}, error) {
+func attributeValueToInterface(av types.AttributeValue, opts ConversionOptions) (interface{}, error) {
 	switch v := av.(type) {
 	case *types.AttributeValueMemberS:
 		return v.Value, nil
 	case *types.AttributeValueMemberN:
-		return v.Value, nil
+		return jsonNumber(v.Value), nil
 	case *types.AttributeValueMemberBOOL:
 		return v.Value, nil
 	case *types.AttributeValueMemberSS:
 		return v.Value, nil
 	case *types.AttributeValueMemberNS:
-		return v.Value, nil
+		numberSet := make([]interface{}, len(v.Value))
+		for i, n := range v.Value {
+			numberSet[i] = jsonNumber(n)
+		}
+		return numberSet, nil
 	case *types.AttributeValueMemberL:
 		list := make([]interface{}, len(v.Value))
 		for i, item := range v.Value {
-			val, err := attributeValueToInterface(item)
+			val, err := attributeValueToInterface(item, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -45,7 +90,7 @@ func attributeValueToInterface(av types.AttributeValue) (interface{}, error) {
 	case *types.AttributeValueMemberM:
 		m := make(map[string]interface{})
 		for key, item := range v.Value {
-			val, err := attributeValueToInterface(item)
+			val, err := attributeValueToInterface(item, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -55,8 +100,18 @@ func attributeValueToInterface(av types.AttributeValue) (interface{}, error) {
 	case *types.AttributeValueMemberNULL:
 		return nil, nil
 	case *types.AttributeValueMemberB:
+		if opts.DisplayBinary {
+			return displayBinary(v.Value), nil
+		}
 		return v.Value, nil // Binary data, returned as []byte
 	case *types.AttributeValueMemberBS:
+		if opts.DisplayBinary {
+			binarySet := make([]string, len(v.Value))
+			for i, b := range v.Value {
+				binarySet[i] = displayBinary(b)
+			}
+			return binarySet, nil
+		}
 		binarySet := make([][]byte, len(v.Value))
 		for i, b := range v.Value {
 			binarySet[i] = b
@@ -66,3 +121,79 @@ func attributeValueToInterface(av types.AttributeValue) (interface{}, error) {
 		return nil, fmt.Errorf("unsupported AttributeValue type %T", v)
 	}
 }
+
+// displayBinary describes a binary value with its length and a base64 preview
+func displayBinary(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	if len(encoded) > binaryPreviewLength {
+		encoded = encoded[:binaryPreviewLength] + "..."
+	}
+	return fmt.Sprintf("<binary %d bytes: %s>", len(data), encoded)
+}
+
+// ParseBinaryValue reads a binary value back from its display form, such as
+// "<binary 5 bytes: SGVsbG8=>", or from plain base64. Display forms that were
+// shortened can't be read back.
+func ParseBinaryValue(value string) ([]byte, error) {
+	encoded := value
+	if strings.HasPrefix(value, "<binary ") && strings.HasSuffix(value, ">") {
+		_, preview, ok := strings.Cut(strings.TrimSuffix(value, ">"), ": ")
+		if !ok {
+			return nil, fmt.Errorf("malformed binary value %q", value)
+		}
+		if strings.HasSuffix(preview, "...") {
+			return nil, fmt.Errorf("binary value %q is shortened and can't be read back", value)
+		}
+		encoded = preview
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("binary value must be base64 encoded: %w", err)
+	}
+	return data, nil
+}
+
+// jsonNumber keeps a DynamoDB number as a JSON number without going through
+// float64, so large or high-precision values aren't rounded. Values that
+// aren't valid JSON numbers are kept as strings.
+func jsonNumber(value string) interface{} {
+	number := json.Number(value)
+	if _, err := json.Marshal(number); err != nil {
+		return value
+	}
+	return number
+}
+
+// FindLossyAttribute returns the path of the first attribute whose plain JSON
+// form can't be converted back to the same DynamoDB value: binaries, which
+// are only shown as a preview, and sets, which come back as lists
+func FindLossyAttribute(item map[string]types.AttributeValue) (string, bool) {
+	for name, value := range item {
+		if path, ok := lossyAttributePath(name, value); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+func lossyAttributePath(path string, av types.AttributeValue) (string, bool) {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberB, *types.AttributeValueMemberBS,
+		*types.AttributeValueMemberSS, *types.AttributeValueMemberNS:
+		return path, true
+	case *types.AttributeValueMemberL:
+		for i, item := range v.Value {
+			if found, ok := lossyAttributePath(fmt.Sprintf("%s[%d]", path, i), item); ok {
+				return found, true
+			}
+		}
+	case *types.AttributeValueMemberM:
+		for name, item := range v.Value {
+			if found, ok := lossyAttributePath(path+"."+name, item); ok {
+				return found, true
+			}
+		}
+	}
+	return "", false
+}
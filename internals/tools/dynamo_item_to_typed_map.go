@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamoItemToTypedMap converts a DynamoDB item to a Go map that keeps the
+// type of every attribute, in the same shape the AWS CLI prints items:
+// {"id": {"S": "123"}, "count": {"N": "123"}}. Binary values are base64
+// encoded.
+func DynamoItemToTypedMap(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for key, value := range item {
+		var err error
+		result[key], err = typedAttributeValue(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// MarshalTypedJSON encodes an item in the typed form of DynamoItemToTypedMap,
+// as the AWS CLI expects it for keys and expression values
+func MarshalTypedJSON(item map[string]types.AttributeValue) (string, error) {
+	typed, err := DynamoItemToTypedMap(item)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(typed)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// typedAttributeValue wraps a value in a map keyed by its DynamoDB type
+func typedAttributeValue(av types.AttributeValue) (map[string]interface{}, error) {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return map[string]interface{}{"S": v.Value}, nil
+	case *types.AttributeValueMemberN:
+		return map[string]interface{}{"N": v.Value}, nil
+	case *types.AttributeValueMemberBOOL:
+		return map[string]interface{}{"BOOL": v.Value}, nil
+	case *types.AttributeValueMemberNULL:
+		return map[string]interface{}{"NULL": true}, nil
+	case *types.AttributeValueMemberB:
+		return map[string]interface{}{"B": base64.StdEncoding.EncodeToString(v.Value)}, nil
+	case *types.AttributeValueMemberSS:
+		return map[string]interface{}{"SS": v.Value}, nil
+	case *types.AttributeValueMemberNS:
+		return map[string]interface{}{"NS": v.Value}, nil
+	case *types.AttributeValueMemberBS:
+		binarySet := make([]string, len(v.Value))
+		for i, b := range v.Value {
+			binarySet[i] = base64.StdEncoding.EncodeToString(b)
+		}
+		return map[string]interface{}{"BS": binarySet}, nil
+	case *types.AttributeValueMemberL:
+		list := make([]interface{}, len(v.Value))
+		for i, item := range v.Value {
+			val, err := typedAttributeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = val
+		}
+		return map[string]interface{}{"L": list}, nil
+	case *types.AttributeValueMemberM:
+		m, err := DynamoItemToTypedMap(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"M": m}, nil
+	default:
+		return nil, fmt.Errorf("unsupported AttributeValue type %T", v)
+	}
+}
+
+// UnmarshalTypedKey decodes a key written by MarshalTypedJSON. Key attributes
+// are strings, numbers or binary, so only those types are accepted.
+func UnmarshalTypedKey(data string) (map[string]types.AttributeValue, error) {
+	var typed map[string]map[string]string
+	if err := json.Unmarshal([]byte(data), &typed); err != nil {
+		return nil, err
+	}
+
+	key := make(map[string]types.AttributeValue, len(typed))
+	for name, value := range typed {
+		switch {
+		case len(value) != 1:
+			return nil, fmt.Errorf("key attribute %s must have exactly one type", name)
+		case value["S"] != "":
+			key[name] = &types.AttributeValueMemberS{Value: value["S"]}
+		case value["N"] != "":
+			key[name] = &types.AttributeValueMemberN{Value: value["N"]}
+		case value["B"] != "":
+			b, err := base64.StdEncoding.DecodeString(value["B"])
+			if err != nil {
+				return nil, fmt.Errorf("key attribute %s: %w", name, err)
+			}
+			key[name] = &types.AttributeValueMemberB{Value: b}
+		default:
+			return nil, fmt.Errorf("key attribute %s has an unsupported type", name)
+		}
+	}
+	return key, nil
+}
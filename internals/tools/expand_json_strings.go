@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+const (
+	// maxEmbeddedJSONDepth bounds how many levels of JSON inside strings are
+	// expanded, since each expanded value may itself hold more
+	maxEmbeddedJSONDepth = 5
+	// maxEmbeddedJSONSize leaves strings longer than this alone, so a huge
+	// payload doesn't blow up the view
+	maxEmbeddedJSONSize = 256 * 1024
+)
+
+// ExpandJSONStrings returns rawJSON with every string value that holds a JSON
+// object or array replaced by the decoded value, so it is pretty-printed with
+// the rest instead of shown as one escaped line. Expansion is limited in
+// depth and size.
+func ExpandJSONStrings(rawJSON string) (string, error) {
+	value, err := decodeJSON(rawJSON)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(expandStrings(value, maxEmbeddedJSONDepth))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func expandStrings(value interface{}, depth int) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			v[key] = expandStrings(element, depth)
+		}
+		return v
+	case []interface{}:
+		for i, element := range v {
+			v[i] = expandStrings(element, depth)
+		}
+		return v
+	case string:
+		if depth <= 0 || len(v) > maxEmbeddedJSONSize {
+			return v
+		}
+		trimmed := strings.TrimSpace(v)
+		if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") || !json.Valid([]byte(trimmed)) {
+			return v
+		}
+		embedded, err := decodeJSON(trimmed)
+		if err != nil {
+			return v
+		}
+		return expandStrings(embedded, depth-1)
+	default:
+		return v
+	}
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers exact
+func decodeJSON(rawJSON string) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(rawJSON)))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExtractPath returns the part of a decoded JSON value found at a dotted path
+// such as "address.city" or "orders[0].total". Array elements can be written
+// either as "orders[0]" or as "orders.0".
+func ExtractPath(obj interface{}, path string) (interface{}, error) {
+	segments, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := obj
+	for i, segment := range segments {
+		walked := strings.Join(segments[:i+1], ".")
+
+		switch value := current.(type) {
+		case map[string]interface{}:
+			next, ok := value[segment]
+			if !ok {
+				return nil, fmt.Errorf("path %q doesn't exist: no attribute %q", walked, segment)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil {
+				return nil, fmt.Errorf("path %q: %q is a list, expected an index", walked, strings.Join(segments[:i], "."))
+			}
+			if index < 0 || index >= len(value) {
+				return nil, fmt.Errorf("path %q doesn't exist: index %d is out of range (length %d)", walked, index, len(value))
+			}
+			current = value[index]
+		default:
+			return nil, fmt.Errorf("path %q doesn't exist: %q has no attributes", walked, strings.Join(segments[:i], "."))
+		}
+	}
+
+	return current, nil
+}
+
+// splitPath breaks a path into attribute names and list indices
+func splitPath(path string) ([]string, error) {
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if name != "" {
+			segments = append(segments, name)
+		}
+		for rest != "" {
+			index, after, ok := strings.Cut(rest, "]")
+			if !ok || index == "" {
+				return nil, fmt.Errorf("invalid path %q: unclosed or empty [ ]", path)
+			}
+			segments = append(segments, index)
+			rest = strings.TrimPrefix(after, "[")
+			if after != "" && !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("invalid path %q: unexpected %q after ]", path, after)
+			}
+		}
+		if name == "" && !strings.Contains(part, "[") {
+			return nil, fmt.Errorf("invalid path %q: empty segment", path)
+		}
+	}
+	return segments, nil
+}
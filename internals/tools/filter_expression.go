@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// FilterExpression is a DynamoDB filter expression together with the
+// attribute names and values it refers to
+type FilterExpression struct {
+	Expression string
+	Names      map[string]string
+	Values     map[string]types.AttributeValue
+}
+
+var (
+	comparisonPattern = regexp.MustCompile(`^([A-Za-z0-9_.\-]+)\s*(<=|>=|<>|=|<|>)\s*(.+)$`)
+	functionPattern   = regexp.MustCompile(`^(begins_with|contains|attribute_exists|attribute_not_exists)\s*\((.*)\)$`)
+)
+
+// ParseFilterExpression turns a simple condition syntax into a DynamoDB filter
+// expression. Conditions are joined with "and" and each is one of:
+//
+//	status = "active"        (also <, >, <=, >=, <>)
+//	begins_with(sk, "ORDER#")
+//	contains(tags, "red")
+//	attribute_exists(deletedAt)
+//	attribute_not_exists(deletedAt)
+//
+// Dotted names such as address.city refer to nested attributes. Quoted
+// values are strings, numbers are numbers, true and false are booleans and
+// null is NULL; any other bare word is taken as a string.
+func ParseFilterExpression(input string) (*FilterExpression, error) {
+	filter := &FilterExpression{
+		Names:  make(map[string]string),
+		Values: make(map[string]types.AttributeValue),
+	}
+
+	var conditions []string
+	for _, condition := range splitConditions(strings.TrimSpace(input)) {
+		condition = strings.TrimSpace(condition)
+		if condition == "" {
+			return nil, fmt.Errorf("empty condition in %q", input)
+		}
+
+		expression, err := filter.parseCondition(condition)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, expression)
+	}
+
+	filter.Expression = strings.Join(conditions, " AND ")
+	if len(filter.Values) == 0 {
+		filter.Values = nil
+	}
+
+	return filter, nil
+}
+
+// parseCondition converts one condition, registering its names and values
+func (f *FilterExpression) parseCondition(condition string) (string, error) {
+	if match := functionPattern.FindStringSubmatch(condition); match != nil {
+		function, args := match[1], splitArguments(match[2])
+
+		switch function {
+		case "attribute_exists", "attribute_not_exists":
+			if len(args) != 1 || args[0] == "" {
+				return "", fmt.Errorf("%s takes one attribute name, got %q", function, condition)
+			}
+			name, err := f.path(args[0])
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s(%s)", function, name), nil
+		default:
+			if len(args) != 2 {
+				return "", fmt.Errorf("%s takes an attribute name and a value, got %q", function, condition)
+			}
+			name, err := f.path(args[0])
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s(%s, %s)", function, name, f.value(args[1])), nil
+		}
+	}
+
+	if match := comparisonPattern.FindStringSubmatch(condition); match != nil {
+		name, err := f.path(match[1])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s %s", name, match[2], f.value(match[3])), nil
+	}
+
+	return "", fmt.Errorf("can't parse condition %q", condition)
+}
+
+// path registers a placeholder for each segment of a dotted attribute path,
+// so address.city refers to the nested attribute rather than one named
+// "address.city"
+func (f *FilterExpression) path(attribute string) (string, error) {
+	segments := strings.Split(attribute, ".")
+	for i, segment := range segments {
+		if segment == "" {
+			return "", fmt.Errorf("invalid attribute path %q", attribute)
+		}
+		segments[i] = f.name(segment)
+	}
+	return strings.Join(segments, "."), nil
+}
+
+// name registers an attribute name placeholder
+func (f *FilterExpression) name(attribute string) string {
+	for placeholder, name := range f.Names {
+		if name == attribute {
+			return placeholder
+		}
+	}
+
+	placeholder := fmt.Sprintf("#n%d", len(f.Names))
+	f.Names[placeholder] = attribute
+	return placeholder
+}
+
+// value registers a value placeholder, inferring its type from the syntax
+func (f *FilterExpression) value(raw string) string {
+	placeholder := fmt.Sprintf(":v%d", len(f.Values))
+	f.Values[placeholder] = filterValue(strings.TrimSpace(raw))
+	return placeholder
+}
+
+func filterValue(raw string) types.AttributeValue {
+	if len(raw) >= 2 && (raw[0] == '"' && raw[len(raw)-1] == '"' || raw[0] == '\'' && raw[len(raw)-1] == '\'') {
+		if unquoted, err := strconv.Unquote(`"` + raw[1:len(raw)-1] + `"`); err == nil {
+			return &types.AttributeValueMemberS{Value: unquoted}
+		}
+		return &types.AttributeValueMemberS{Value: raw[1 : len(raw)-1]}
+	}
+
+	switch raw {
+	case "true", "false":
+		return &types.AttributeValueMemberBOOL{Value: raw == "true"}
+	case "null":
+		return &types.AttributeValueMemberNULL{Value: true}
+	}
+
+	var number json.Number
+	if err := json.Unmarshal([]byte(raw), &number); err == nil {
+		return &types.AttributeValueMemberN{Value: number.String()}
+	}
+
+	return &types.AttributeValueMemberS{Value: raw}
+}
+
+// splitConditions splits the input on "and", ignoring it inside quotes
+func splitConditions(input string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ' ' && len(input)-i > 5 && strings.EqualFold(input[i:i+5], " and "):
+			parts = append(parts, input[start:i])
+			start = i + 5
+			i += 4
+		}
+	}
+
+	return append(parts, input[start:])
+}
+
+// splitArguments splits function arguments on commas outside of quotes
+func splitArguments(args string) []string {
+	var parts []string
+	var current strings.Builder
+	var quote rune
+
+	for _, r := range args {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			current.WriteRune(r)
+		case r == ',':
+			parts = append(parts, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	return append(parts, strings.TrimSpace(current.String()))
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// AttributeFilter returns a list filter for single-line JSON rows that only
+// matches the term against the value of one top-level attribute, ignoring
+// attribute names and the rest of the row. Matching is case-insensitive.
+func AttributeFilter(attribute string) list.FilterFunc {
+	return func(term string, targets []string) []list.Rank {
+		term = strings.ToLower(term)
+
+		var ranks []list.Rank
+		for i, row := range targets {
+			matched, ok := matchAttributeValue(row, attribute, term)
+			if ok {
+				ranks = append(ranks, list.Rank{Index: i, MatchedIndexes: matched})
+			}
+		}
+		return ranks
+	}
+}
+
+// matchAttributeValue reports whether the value of attribute in row contains
+// term. It also returns the rune positions of the match within row so the
+// list can highlight it; they're left empty when the value can't be located.
+func matchAttributeValue(row, attribute, term string) ([]int, bool) {
+	decoder := json.NewDecoder(strings.NewReader(row))
+	decoder.UseNumber()
+
+	var item map[string]interface{}
+	if err := decoder.Decode(&item); err != nil {
+		return nil, false
+	}
+
+	value, ok := item[attribute]
+	if !ok {
+		return nil, false
+	}
+
+	// Rows are produced by json.Marshal, so the value appears in the row
+	// exactly as it encodes here
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	encoded := string(data)
+
+	// Strings are matched without their surrounding quotes
+	text := encoded
+	if str, ok := value.(string); ok {
+		text = str
+	}
+	if !strings.Contains(strings.ToLower(text), term) {
+		return nil, false
+	}
+
+	key, _ := json.Marshal(attribute)
+	keyStart := strings.Index(row, string(key)+":")
+	if keyStart < 0 {
+		return nil, true
+	}
+	valueStart := keyStart + len(key) + 1
+	if !strings.HasPrefix(row[valueStart:], encoded) {
+		return nil, true
+	}
+
+	matchStart := strings.Index(strings.ToLower(encoded), term)
+	if matchStart < 0 || len(strings.ToLower(encoded)) != len(encoded) {
+		return nil, true
+	}
+
+	start := utf8.RuneCountInString(row[:valueStart+matchStart])
+	matched := make([]int, utf8.RuneCountInString(encoded[matchStart:matchStart+len(term)]))
+	for i := range matched {
+		matched[i] = start + i
+	}
+	return matched, true
+}
@@ -1,25 +1,199 @@
 package tools
 
 import (
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/list"
 )
 
-func FilterTables(tables []string, filterText string) []string {
-	filterText = strings.ToLower(filterText)
-	var filtered []string
+// PrefixFilter matches table names starting with the term, ignoring case. In
+// multi-region mode names look like "region/table", and the term may match
+// either the start of the whole entry or the start of the table name, so both
+// "eu-west-1/" and "orders" narrow the list down.
+func PrefixFilter(term string, targets []string) []list.Rank {
+	term = strings.ToLower(term)
+
+	var ranks []list.Rank
+	for i, target := range targets {
+		lower := strings.ToLower(target)
+
+		start := -1
+		if strings.HasPrefix(lower, term) {
+			start = 0
+		} else if _, table, ok := strings.Cut(lower, "/"); ok && strings.HasPrefix(table, term) {
+			start = len(lower) - len(table)
+		}
+		if start < 0 {
+			continue
+		}
+
+		offset := utf8.RuneCountInString(target[:start])
+		matched := make([]int, utf8.RuneCountInString(term))
+		for j := range matched {
+			matched[j] = offset + j
+		}
+		ranks = append(ranks, list.Rank{Index: i, MatchedIndexes: matched})
+	}
+	return ranks
+}
 
-	for _, table := range tables {
-		tableLower := strings.ToLower(table)
-		matchIndex := 0
+// FilterTables matches table names that contain the characters of the term in
+// order, ignoring case, e.g. "usrevt" matches "users-events". Unlike the
+// default fuzzy filter it keeps the list in its original order.
+func FilterTables(term string, targets []string) []list.Rank {
+	needle := []rune(strings.ToLower(term))
 
-		for i := 0; i < len(tableLower) && matchIndex < len(filterText); i++ {
-			if tableLower[i] == filterText[matchIndex] {
-				matchIndex++
+	var ranks []list.Rank
+	for i, target := range targets {
+		var matched []int
+		j := 0
+		for index, r := range []rune(strings.ToLower(target)) {
+			if j < len(needle) && r == needle[j] {
+				matched = append(matched, index)
+				j++
 			}
 		}
-		if matchIndex == len(filterText) {
-			filtered = append(filtered, table)
+		if j == len(needle) {
+			ranks = append(ranks, list.Rank{Index: i, MatchedIndexes: matched})
 		}
 	}
-	return filtered
+	return ranks
+}
+
+// Scores of RankTables. Every matched character scores; runs of consecutive
+// characters, characters starting a word and a match at the start of the name
+// score more, and the characters skipped between matches cost.
+const (
+	scoreMatch       = 10
+	bonusConsecutive = 8
+	bonusWordStart   = 8
+	bonusNameStart   = 12
+	penaltyGap       = 1
+)
+
+// TableScore is a table name matched by RankTables with its score, higher
+// being better
+type TableScore struct {
+	Name  string
+	Score int
+
+	index          int
+	matchedIndexes []int
+}
+
+// RankTables matches table names like FilterTables, containing the characters
+// of the term in order, and sorts them best match first: "ev" puts
+// "events" ahead of "users-events" and both ahead of "user_reviews". Equal
+// scores keep the shorter name first, then the original order.
+func RankTables(term string, targets []string) []TableScore {
+	needle := []rune(strings.ToLower(term))
+
+	var scores []TableScore
+	for i, target := range targets {
+		score, matched, ok := scoreTable(needle, []rune(target))
+		if ok {
+			scores = append(scores, TableScore{Name: target, Score: score, index: i, matchedIndexes: matched})
+		}
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return len(scores[i].Name) < len(scores[j].Name)
+	})
+	return scores
+}
+
+// RankedFilter is a list filter that orders tables by RankTables
+func RankedFilter(term string, targets []string) []list.Rank {
+	scores := RankTables(term, targets)
+	ranks := make([]list.Rank, len(scores))
+	for i, score := range scores {
+		ranks[i] = list.Rank{Index: score.index, MatchedIndexes: score.matchedIndexes}
+	}
+	return ranks
+}
+
+// scoreTable finds the best scoring placement of needle in name, returning
+// its score and the matched rune indexes
+func scoreTable(needle, name []rune) (int, []int, bool) {
+	if len(needle) == 0 {
+		return 0, nil, true
+	}
+	lower := []rune(strings.ToLower(string(name)))
+	if len(lower) != len(name) {
+		// Lowercasing changed the length; match the name as it is
+		lower = name
+	}
+
+	// best[i][j] is the best score of needle[:i+1] with needle[i] matched at
+	// name[j], or noMatch; from[i][j] is where needle[i-1] was matched
+	const noMatch = -1 << 30
+	best := make([][]int, len(needle))
+	from := make([][]int, len(needle))
+	for i := range needle {
+		best[i] = make([]int, len(lower))
+		from[i] = make([]int, len(lower))
+		for j := range lower {
+			best[i][j] = noMatch
+			if lower[j] != needle[i] {
+				continue
+			}
+
+			base := scoreMatch + positionBonus(name, j)
+			if i == 0 {
+				best[i][j] = base
+				continue
+			}
+			for k := i - 1; k < j; k++ {
+				if best[i-1][k] == noMatch {
+					continue
+				}
+				score := best[i-1][k] + base - penaltyGap*(j-k-1)
+				if k == j-1 {
+					score += bonusConsecutive
+				}
+				if score > best[i][j] {
+					best[i][j], from[i][j] = score, k
+				}
+			}
+		}
+	}
+
+	last := len(needle) - 1
+	end := -1
+	for j := range lower {
+		if best[last][j] != noMatch && (end < 0 || best[last][j] > best[last][end]) {
+			end = j
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	matched := make([]int, len(needle))
+	for i, j := last, end; i >= 0; i-- {
+		matched[i] = j
+		j = from[i][j]
+	}
+	return best[last][end], matched, true
+}
+
+// positionBonus rewards matching the character at index of name when it
+// starts the name, the table part of "region/table", or a word: after an
+// underscore, hyphen, dot or space, or an uppercase letter after a lowercase
+// one as in "userEvents"
+func positionBonus(name []rune, index int) int {
+	if index == 0 || name[index-1] == '/' {
+		return bonusNameStart
+	}
+	previous := name[index-1]
+	if strings.ContainsRune("_-. ", previous) || unicode.IsLower(previous) && unicode.IsUpper(name[index]) {
+		return bonusWordStart
+	}
+	return 0
 }
@@ -0,0 +1,20 @@
+package tools
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRankTablesOrdersBestMatchFirst(t *testing.T) {
+	tables := []string{"user_reviews", "users-events", "events", "archived", "eu-west-1/events_v2"}
+
+	var names []string
+	for _, match := range RankTables("ev", tables) {
+		names = append(names, match.Name)
+	}
+
+	want := []string{"events", "eu-west-1/events_v2", "users-events", "user_reviews"}
+	if !slices.Equal(names, want) {
+		t.Errorf("RankTables(%q) = %v, want %v", "ev", names, want)
+	}
+}
@@ -0,0 +1,44 @@
+package tools
+
+import "strconv"
+
+// Flatten expands nested attributes into top-level ones named by their path,
+// e.g. address.city and tags[0], so each ends up in a column of its own. Up to
+// maxDepth levels are expanded; values nested deeper are kept whole, as are
+// empty maps and lists.
+func Flatten(obj map[string]interface{}, maxDepth int) map[string]interface{} {
+	flat := make(map[string]interface{}, len(obj))
+	for name, value := range obj {
+		flattenValue(flat, name, value, maxDepth)
+	}
+	return flat
+}
+
+// flattenValue adds value to flat under name, expanding it when it is a
+// non-empty map or list and depth levels are left
+func flattenValue(flat map[string]interface{}, name string, value interface{}, depth int) {
+	if depth <= 0 {
+		flat[name] = value
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			break
+		}
+		for key, nested := range v {
+			flattenValue(flat, name+"."+key, nested, depth-1)
+		}
+		return
+	case []interface{}:
+		if len(v) == 0 {
+			break
+		}
+		for i, nested := range v {
+			flattenValue(flat, name+"["+strconv.Itoa(i)+"]", nested, depth-1)
+		}
+		return
+	}
+	flat[name] = value
+}
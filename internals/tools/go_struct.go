@@ -0,0 +1,272 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// initialisms are name parts written in capitals in Go identifiers
+var initialisms = map[string]bool{
+	"api": true, "arn": true, "http": true, "id": true, "ip": true,
+	"json": true, "ttl": true, "url": true, "uuid": true,
+}
+
+// GoStructFromTyped generates Go struct definitions with dynamodbav tags for
+// an item in the typed form of DynamoItemToTypedMap. Nested maps become
+// nested structs, lists become slices and sets are tagged as sets.
+func GoStructFromTyped(name string, typed map[string]interface{}) (string, error) {
+	g := &structGenerator{names: make(map[string]bool)}
+	g.define(GoIdentifier(name, "Item"), []map[string]interface{}{typed})
+	return g.source()
+}
+
+// GoStructFromJSON generates Go struct definitions like GoStructFromTyped for
+// an item in plain JSON, as the rows are shown. Types are guessed from the
+// JSON, so sets come out as plain slices and binary values as strings.
+func GoStructFromJSON(name string, rawJSON string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(rawJSON))
+	decoder.UseNumber()
+
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	return GoStructFromTyped(name, jsonToTyped(obj))
+}
+
+// jsonToTyped wraps plain JSON values in the typed form
+func jsonToTyped(obj map[string]interface{}) map[string]interface{} {
+	typed := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		typed[key] = jsonValueToTyped(value)
+	}
+	return typed
+}
+
+func jsonValueToTyped(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case string:
+		return map[string]interface{}{"S": v}
+	case json.Number:
+		return map[string]interface{}{"N": v.String()}
+	case bool:
+		return map[string]interface{}{"BOOL": v}
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, element := range v {
+			list[i] = jsonValueToTyped(element)
+		}
+		return map[string]interface{}{"L": list}
+	case map[string]interface{}:
+		return map[string]interface{}{"M": jsonToTyped(v)}
+	default:
+		return map[string]interface{}{"NULL": true}
+	}
+}
+
+type structField struct {
+	name   string
+	goType string
+	tag    string
+}
+
+type structDef struct {
+	name   string
+	fields []structField
+}
+
+// structGenerator collects the struct definitions an item needs, the
+// top-level one first
+type structGenerator struct {
+	structs []structDef
+	names   map[string]bool
+}
+
+// define adds a struct holding the union of the attributes of items and
+// returns its name
+func (g *structGenerator) define(name string, items []map[string]interface{}) string {
+	name = g.uniqueName(name)
+	index := len(g.structs)
+	g.structs = append(g.structs, structDef{name: name})
+
+	values := make(map[string][]map[string]interface{})
+	for _, item := range items {
+		for attribute, value := range item {
+			if typed, ok := value.(map[string]interface{}); ok {
+				values[attribute] = append(values[attribute], typed)
+			}
+		}
+	}
+
+	attributes := make([]string, 0, len(values))
+	for attribute := range values {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+
+	used := make(map[string]bool)
+	var fields []structField
+	for _, attribute := range attributes {
+		fieldName := GoIdentifier(attribute, "Field")
+		for i := 2; used[fieldName]; i++ {
+			fieldName = fmt.Sprintf("%s%d", GoIdentifier(attribute, "Field"), i)
+		}
+		used[fieldName] = true
+
+		goType, option := g.goType(name+fieldName, values[attribute])
+		fields = append(fields, structField{
+			name:   fieldName,
+			goType: goType,
+			tag:    fmt.Sprintf("`dynamodbav:%q`", attribute+option),
+		})
+	}
+
+	g.structs[index].fields = fields
+	return name
+}
+
+// goType picks the Go type fitting every one of the typed values, and the
+// tag option it needs. Values that disagree are typed as interface{}.
+func (g *structGenerator) goType(name string, values []map[string]interface{}) (string, string) {
+	kind := typedKind(values[0])
+	for _, value := range values[1:] {
+		if typedKind(value) != kind {
+			return "interface{}", ""
+		}
+	}
+
+	switch kind {
+	case "S":
+		return "string", ""
+	case "N":
+		return numberType(values, "N"), ""
+	case "BOOL":
+		return "bool", ""
+	case "B":
+		return "[]byte", ""
+	case "SS":
+		return "[]string", ",stringset"
+	case "NS":
+		return "[]" + numberType(values, "NS"), ",numberset"
+	case "BS":
+		return "[][]byte", ",binaryset"
+	case "M":
+		var items []map[string]interface{}
+		for _, value := range values {
+			if item, ok := value["M"].(map[string]interface{}); ok {
+				items = append(items, item)
+			}
+		}
+		return g.define(name, items), ""
+	case "L":
+		var elements []map[string]interface{}
+		for _, value := range values {
+			list, _ := value["L"].([]interface{})
+			for _, element := range list {
+				if typed, ok := element.(map[string]interface{}); ok {
+					elements = append(elements, typed)
+				}
+			}
+		}
+		if len(elements) == 0 {
+			return "[]interface{}", ""
+		}
+		elementType, _ := g.goType(name, elements)
+		return "[]" + elementType, ""
+	default:
+		return "interface{}", ""
+	}
+}
+
+// typedKind returns the DynamoDB type a typed value is keyed by
+func typedKind(value map[string]interface{}) string {
+	for kind := range value {
+		return kind
+	}
+	return ""
+}
+
+// numberType is int64 when every number is a whole number, float64 otherwise
+func numberType(values []map[string]interface{}, kind string) string {
+	for _, value := range values {
+		var numbers []string
+		switch v := value[kind].(type) {
+		case string:
+			numbers = []string{v}
+		case []string:
+			numbers = v
+		case []interface{}:
+			for _, n := range v {
+				numbers = append(numbers, fmt.Sprint(n))
+			}
+		}
+		for _, number := range numbers {
+			if strings.ContainsAny(number, ".eE") {
+				return "float64"
+			}
+		}
+	}
+	return "int64"
+}
+
+func (g *structGenerator) uniqueName(name string) string {
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// source renders the collected structs as gofmt'ed Go code
+func (g *structGenerator) source() (string, error) {
+	var b strings.Builder
+	for i, def := range g.structs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "type %s struct {\n", def.name)
+		for _, field := range def.fields {
+			fmt.Fprintf(&b, "\t%s %s %s\n", field.name, field.goType, field.tag)
+		}
+		b.WriteString("}\n")
+	}
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+// GoIdentifier turns an attribute or table name such as "user_id" into an
+// exported Go identifier such as "UserID", or fallback if nothing is left
+func GoIdentifier(name, fallback string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, part := range parts {
+		if initialisms[strings.ToLower(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		b.WriteString(string(unicode.ToUpper(runes[0])) + string(runes[1:]))
+	}
+
+	identifier := b.String()
+	if identifier == "" {
+		return fallback
+	}
+	if unicode.IsDigit([]rune(identifier)[0]) {
+		identifier = fallback + identifier
+	}
+	return identifier
+}
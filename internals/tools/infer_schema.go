@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+)
+
+// Schema describes the shape of a sample of items: which top-level attributes
+// they have, with which types and how often
+type Schema struct {
+	Items      int // number of items the schema was inferred from
+	Attributes []SchemaAttribute
+}
+
+// SchemaAttribute is one attribute seen in a sample of items
+type SchemaAttribute struct {
+	Name  string
+	Count int            // items that have the attribute
+	Types map[string]int // items per observed type, e.g. "S" or "N"
+}
+
+// Presence returns the share of the sampled items that have the attribute,
+// between 0 and 1
+func (a SchemaAttribute) Presence(items int) float64 {
+	if items == 0 {
+		return 0
+	}
+	return float64(a.Count) / float64(items)
+}
+
+// TypeNames returns the observed types, the most common first
+func (a SchemaAttribute) TypeNames() []string {
+	names := make([]string, 0, len(a.Types))
+	for name := range a.Types {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if a.Types[names[i]] != a.Types[names[j]] {
+			return a.Types[names[i]] > a.Types[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// InferSchema aggregates the attributes of single-line JSON rows. Types are
+// named after DynamoDB's (S, N, BOOL, NULL, L, M) but are read from the JSON
+// the rows are displayed as, so sets show up as L and binary values as S.
+// Attributes are ordered by how many rows have them, then by name.
+func InferSchema(rows []string) Schema {
+	schema := Schema{}
+	attributes := make(map[string]*SchemaAttribute)
+
+	for _, row := range rows {
+		var item map[string]interface{}
+		if err := json.Unmarshal([]byte(row), &item); err != nil {
+			log.Printf("Failed to parse row for schema: %v", err)
+			continue
+		}
+		schema.Items++
+
+		for name, value := range item {
+			attribute, ok := attributes[name]
+			if !ok {
+				attribute = &SchemaAttribute{Name: name, Types: make(map[string]int)}
+				attributes[name] = attribute
+			}
+			attribute.Count++
+			attribute.Types[jsonTypeName(value)]++
+		}
+	}
+
+	for _, attribute := range attributes {
+		schema.Attributes = append(schema.Attributes, *attribute)
+	}
+	sort.Slice(schema.Attributes, func(i, j int) bool {
+		a, b := schema.Attributes[i], schema.Attributes[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+
+	return schema
+}
+
+// jsonTypeName names the DynamoDB type a decoded JSON value corresponds to
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "S"
+	case float64:
+		return "N"
+	case bool:
+		return "BOOL"
+	case []interface{}:
+		return "L"
+	case map[string]interface{}:
+		return "M"
+	default:
+		return "NULL"
+	}
+}
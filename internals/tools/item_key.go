@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// KeyAttributes returns the names of the table's key attributes
+func KeyAttributes(table *types.TableDescription) map[string]bool {
+	keys := make(map[string]bool, len(table.KeySchema))
+	for _, element := range table.KeySchema {
+		keys[*element.AttributeName] = true
+	}
+	return keys
+}
+
+// ExtractItemKey builds the primary key of an item from its JSON form. Each
+// key value is marshalled according to the attribute type declared in the
+// table's attribute definitions.
+func ExtractItemKey(obj map[string]interface{}, table *types.TableDescription) (map[string]types.AttributeValue, error) {
+	attributeTypes := make(map[string]types.ScalarAttributeType)
+	for _, definition := range table.AttributeDefinitions {
+		attributeTypes[*definition.AttributeName] = definition.AttributeType
+	}
+
+	key := make(map[string]types.AttributeValue)
+	for _, element := range table.KeySchema {
+		name := *element.AttributeName
+
+		value, ok := obj[name]
+		if !ok || value == nil {
+			return nil, fmt.Errorf("item is missing key attribute %q", name)
+		}
+
+		av, err := keyAttributeValue(value, attributeTypes[name])
+		if err != nil {
+			return nil, fmt.Errorf("key attribute %q: %w", name, err)
+		}
+		key[name] = av
+	}
+
+	return key, nil
+}
+
+// keyAttributeValue marshals a key value to the given scalar attribute type
+func keyAttributeValue(value interface{}, attributeType types.ScalarAttributeType) (types.AttributeValue, error) {
+	switch attributeType {
+	case types.ScalarAttributeTypeS:
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %T", value)
+		}
+		return &types.AttributeValueMemberS{Value: str}, nil
+	case types.ScalarAttributeTypeN:
+		switch v := value.(type) {
+		case json.Number:
+			return &types.AttributeValueMemberN{Value: v.String()}, nil
+		case string:
+			return &types.AttributeValueMemberN{Value: v}, nil
+		default:
+			return nil, fmt.Errorf("expected a number, got %T", value)
+		}
+	case types.ScalarAttributeTypeB:
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected binary data, got %T", value)
+		}
+		data, err := ParseBinaryValue(str)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberB{Value: data}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", attributeType)
+	}
+}
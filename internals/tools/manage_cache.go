@@ -1,36 +1,141 @@
 package tools
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 )
 
+// cacheLocks holds one mutex per cache file path. Background refreshes run
+// concurrently with foreground loads, so access to each file is serialized.
+var cacheLocks sync.Map
+
+func lockCacheFile(cacheFilePath string) func() {
+	lock, _ := cacheLocks.LoadOrStore(cacheFilePath, &sync.Mutex{})
+	mu := lock.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// gzipMagic starts every gzip stream, telling compressed caches apart from
+// plain JSON whatever their name
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// compressedSuffix marks cache paths that are written gzip compressed. A
+// cache at such a path may still exist uncompressed without the suffix,
+// written by an earlier version or with compression turned off.
+const compressedSuffix = ".gz"
+
 type Cache struct {
 	Data    []string  `json:"data"`
 	Updated time.Time `json:"updated"`
+
+	// Complete is false for the rows of an interrupted scan, whose Segments
+	// say where each of its segments continues
+	Complete bool             `json:"complete"`
+	Segments []ScanSegmentKey `json:"segments,omitempty"`
+}
+
+// ScanSegmentKey is how far one segment of an interrupted scan got
+type ScanSegmentKey struct {
+	StartKey string `json:"startKey,omitempty"` // key the segment continues from, as MarshalTypedJSON writes it
+	Done     bool   `json:"done"`               // the segment was read to its end
 }
 
+// LoadCache reads a cache file, compressed or not. For a compressed path it
+// falls back to the uncompressed cache an earlier version wrote.
 func LoadCache(cacheFilePath string) (*Cache, error) {
-	file, err := os.Open(cacheFilePath)
+	unlock := lockCacheFile(cacheFilePath)
+	defer unlock()
+
+	path := cacheFilePath
+	file, err := os.Open(path)
+	if os.IsNotExist(err) && strings.HasSuffix(path, compressedSuffix) {
+		path = strings.TrimSuffix(path, compressedSuffix)
+		file, err = os.Open(path)
+	}
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
 	var cache Cache
-	err = json.NewDecoder(file).Decode(&cache)
+	err = decodeCache(file, &cache)
+	if err == nil && cache.Updated.IsZero() {
+		err = errors.New("missing update time")
+	}
+	if err == nil && !cache.Complete && len(cache.Segments) == 0 {
+		// Earlier versions only cached what was read completely
+		cache.Complete = true
+	}
 	if err != nil {
-		return nil, err
+		// A corrupt or partially written cache would keep failing on every
+		// load, so drop it and let the caller fetch fresh data
+		file.Close()
+		log.Printf("Removing corrupt cache file %s: %v", path, err)
+		if removeErr := os.Remove(path); removeErr != nil {
+			log.Printf("Failed to remove corrupt cache file: %v", removeErr)
+		}
+		return nil, fmt.Errorf("corrupt cache file %s: %w", path, err)
 	}
 
 	return &cache, nil
 }
 
-// Save cache to file
+// decodeCache decodes a cache, decompressing it if it starts like gzip
+func decodeCache(r io.Reader, cache *Cache) error {
+	buffered := bufio.NewReader(r)
+	if magic, err := buffered.Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return json.NewDecoder(gz).Decode(cache)
+	}
+	return json.NewDecoder(buffered).Decode(cache)
+}
+
+// RemoveCache deletes a cache file, along with the cache the other of the
+// compressed and uncompressed forms may have left for it
+func RemoveCache(cacheFilePath string) error {
+	unlock := lockCacheFile(cacheFilePath)
+	defer unlock()
+
+	for _, path := range []string{cacheFilePath, otherCacheForm(cacheFilePath)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveCache writes the cache file, gzip compressed when its path ends in .gz
 func SaveCache(data []list.Item, cacheDir string, cacheFilePath string) error {
+	return writeCache(data, nil, cacheDir, cacheFilePath)
+}
+
+// SaveScanProgress writes the rows of an interrupted scan to the cache file,
+// along with where each segment got to, so the scan can be resumed
+func SaveScanProgress(data []list.Item, segments []ScanSegmentKey, cacheDir string, cacheFilePath string) error {
+	return writeCache(data, segments, cacheDir, cacheFilePath)
+}
+
+// writeCache writes the cache file, complete unless segments are given
+func writeCache(data []list.Item, segments []ScanSegmentKey, cacheDir string, cacheFilePath string) error {
 	// Create cache directory if it doesn’t exist
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return err
@@ -42,15 +147,119 @@ func SaveCache(data []list.Item, cacheDir string, cacheFilePath string) error {
 	}
 
 	cache := Cache{
-		Data:    items,
-		Updated: time.Now(),
+		Data:     items,
+		Updated:  time.Now(),
+		Complete: len(segments) == 0,
+		Segments: segments,
 	}
 
-	file, err := os.Create(cacheFilePath)
+	unlock := lockCacheFile(cacheFilePath)
+	defer unlock()
+
+	// Write to a temporary file first and rename it into place, so readers
+	// never see a partially written cache
+	file, err := os.CreateTemp(cacheDir, filepath.Base(cacheFilePath)+".*.tmp")
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	defer os.Remove(file.Name()) // No-op once the rename succeeded
+
+	if err := encodeCache(file, cache, strings.HasSuffix(cacheFilePath, compressedSuffix)); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(file.Name(), cacheFilePath); err != nil {
+		return err
+	}
+
+	// The cache in the other form is superseded and would go stale
+	other := otherCacheForm(cacheFilePath)
+	if err := os.Remove(other); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to remove old cache file %s: %v", other, err)
+	}
+	return nil
+}
+
+// otherCacheForm returns the uncompressed path of a compressed cache path,
+// and the other way round
+func otherCacheForm(cacheFilePath string) string {
+	if strings.HasSuffix(cacheFilePath, compressedSuffix) {
+		return strings.TrimSuffix(cacheFilePath, compressedSuffix)
+	}
+	return cacheFilePath + compressedSuffix
+}
+
+func encodeCache(w io.Writer, cache Cache, compress bool) error {
+	if !compress {
+		return json.NewEncoder(w).Encode(cache)
+	}
+
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(cache); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// CacheInfo describes a cache file on disk
+type CacheInfo struct {
+	Path     string
+	Name     string
+	Size     int64
+	Modified time.Time
+}
+
+// ListCaches returns the cache files in dir, the largest first. Leftover
+// temporary files of interrupted writes are included so they can be removed.
+func ListCaches(dir string) ([]CacheInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	return json.NewEncoder(file).Encode(cache)
+	var caches []CacheInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.Contains(entry.Name(), "_cache.json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// Removed since the directory was read
+			continue
+		}
+		caches = append(caches, CacheInfo{
+			Path:     filepath.Join(dir, entry.Name()),
+			Name:     entry.Name(),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+
+	sort.Slice(caches, func(i, j int) bool {
+		if caches[i].Size != caches[j].Size {
+			return caches[i].Size > caches[j].Size
+		}
+		return caches[i].Name < caches[j].Name
+	})
+	return caches, nil
+}
+
+// DeleteCache removes one cache file. Unlike RemoveCache it deletes exactly
+// the given file, as listed by ListCaches.
+func DeleteCache(path string) error {
+	unlock := lockCacheFile(path)
+	defer unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ParseItemJSON decodes a JSON object, keeping numbers as json.Number so they
+// don't lose precision on their way back to DynamoDB
+func ParseItemJSON(rawJSON string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(rawJSON)))
+	decoder.UseNumber()
+
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, fmt.Errorf("item must be a JSON object")
+	}
+	return obj, nil
+}
+
+// Converts a Go map decoded from JSON into a DynamoDB item
+func MapToDynamoItem(obj map[string]interface{}) (map[string]types.AttributeValue, error) {
+	item := make(map[string]types.AttributeValue)
+	for key, value := range obj {
+		av, err := interfaceToAttributeValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", key, err)
+		}
+		item[key] = av
+	}
+	return item, nil
+}
+
+// Converts a decoded JSON value to a DynamoDB AttributeValue
+func interfaceToAttributeValue(value interface{}) (types.AttributeValue, error) {
+	switch v := value.(type) {
+	case nil:
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	case bool:
+		return &types.AttributeValueMemberBOOL{Value: v}, nil
+	case string:
+		return &types.AttributeValueMemberS{Value: v}, nil
+	case json.Number:
+		return &types.AttributeValueMemberN{Value: v.String()}, nil
+	case float64:
+		return &types.AttributeValueMemberN{Value: strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case []interface{}:
+		list := make([]types.AttributeValue, len(v))
+		for i, item := range v {
+			av, err := interfaceToAttributeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = av
+		}
+		return &types.AttributeValueMemberL{Value: list}, nil
+	case map[string]interface{}:
+		m, err := MapToDynamoItem(v)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberM{Value: m}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LoadPinnedTables reads the pinned tables stored at path, each written as
+// "region/table". A missing file means nothing is pinned.
+func LoadPinnedTables(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []string
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, err
+	}
+
+	pinned := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		pinned[table] = true
+	}
+	return pinned, nil
+}
+
+// SavePinnedTables writes the pinned tables to path as a sorted JSON list,
+// creating its directory if needed
+func SavePinnedTables(pinned map[string]bool, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tables := make([]string, 0, len(pinned))
+	for table, ok := range pinned {
+		if ok {
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+
+	data, err := json.MarshalIndent(tables, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
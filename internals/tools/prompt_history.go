@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// History is a ring buffer of the values entered in a prompt. Once full, each
+// new entry replaces the oldest one.
+type History struct {
+	entries []string
+	size    int
+	start   int // index of the oldest entry once the buffer is full
+}
+
+// NewHistory creates a history of at most size entries, holding the given
+// entries, oldest first
+func NewHistory(size int, entries []string) *History {
+	h := &History{entries: make([]string, 0, size), size: size}
+	for _, entry := range entries {
+		h.Add(entry)
+	}
+	return h
+}
+
+// Add records an entry, unless it is empty or the same as the latest one. It
+// reports whether the entry was added.
+func (h *History) Add(entry string) bool {
+	if entry == "" || h.size <= 0 || h.Len() > 0 && h.Recent(0) == entry {
+		return false
+	}
+
+	if len(h.entries) < h.size {
+		h.entries = append(h.entries, entry)
+		return true
+	}
+	h.entries[h.start] = entry
+	h.start = (h.start + 1) % h.size
+	return true
+}
+
+// Len returns the number of entries
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// Recent returns the i-th most recent entry, 0 being the latest
+func (h *History) Recent(i int) string {
+	return h.entries[(h.start+len(h.entries)-1-i)%len(h.entries)]
+}
+
+// Entries returns the entries, oldest first
+func (h *History) Entries() []string {
+	entries := make([]string, len(h.entries))
+	for i := range entries {
+		entries[i] = h.Recent(len(entries) - 1 - i)
+	}
+	return entries
+}
+
+// LoadHistories reads the histories stored at path by name. A missing file
+// means there is no history yet.
+func LoadHistories(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string][]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var histories map[string][]string
+	if err := json.Unmarshal(data, &histories); err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+// SaveHistories writes the histories to path, creating its directory if
+// needed
+func SaveHistories(histories map[string]*History, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	entries := make(map[string][]string, len(histories))
+	for name, history := range histories {
+		entries[name] = history.Entries()
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadItemsFile reads items from a file holding either a JSON array of objects
+// or one JSON object per line (JSONL). Numbers are kept as json.Number. Items
+// that can't be parsed are reported individually instead of failing the
+// whole file.
+func ReadItemsFile(path string) ([]map[string]interface{}, []error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		return readItemsArray(trimmed)
+	}
+	return readItemsLines(trimmed)
+}
+
+func readItemsArray(data []byte) ([]map[string]interface{}, []error, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw []json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON array: %w", err)
+	}
+
+	var items []map[string]interface{}
+	var itemErrs []error
+	for i, entry := range raw {
+		item, err := ParseItemJSON(string(entry))
+		if err != nil {
+			itemErrs = append(itemErrs, fmt.Errorf("item %d: %w", i+1, err))
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, itemErrs, nil
+}
+
+func readItemsLines(data []byte) ([]map[string]interface{}, []error, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // Items can be up to 400KB
+
+	var items []map[string]interface{}
+	var itemErrs []error
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		item, err := ParseItemJSON(text)
+		if err != nil {
+			itemErrs = append(itemErrs, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return items, itemErrs, nil
+}
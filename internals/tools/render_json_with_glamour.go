@@ -10,10 +10,19 @@ import (
 )
 
 // RenderJSONWithGlamour takes a JSON string, unmarshals it, pretty-prints it, and then applies glamour styling.
-func RenderJSONWithGlamour(rawJSON string) (string, error) {
+// Lines are wrapped at wrapWidth, or 80 columns when it isn't positive.
+func RenderJSONWithGlamour(rawJSON string, wrapWidth int) (string, error) {
+	if wrapWidth <= 0 {
+		wrapWidth = 80
+	}
+
 	// Unmarshal the JSON string to ensure it’s a valid JSON object
+	// Numbers are decoded as json.Number so they keep their exact precision
+	decoder := json.NewDecoder(bytes.NewReader([]byte(rawJSON)))
+	decoder.UseNumber()
+
 	var jsonData interface{}
-	if err := json.Unmarshal([]byte(rawJSON), &jsonData); err != nil {
+	if err := decoder.Decode(&jsonData); err != nil {
 		log.Printf("Failed to unmarshal JSON: %v", err)
 		return "", fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
@@ -34,7 +43,7 @@ func RenderJSONWithGlamour(rawJSON string) (string, error) {
 	// Set up a renderer with a dark theme for glamour
 	renderer, err := glamour.NewTermRenderer(
 		glamour.WithStandardStyle("dark"),
-		glamour.WithWordWrap(80), // Adjust wrap width as needed
+		glamour.WithWordWrap(wrapWidth),
 	)
 	if err != nil {
 		log.Printf("Failed to create glamour renderer: %v", err)
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotateLog rotates the log file at path once it grows beyond maxSize bytes.
+// The current file becomes path.1, path.1 becomes path.2 and so on, keeping at
+// most backups old files.
+func RotateLog(path string, maxSize int64, backups int) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size() < maxSize {
+		return nil
+	}
+
+	// Drop the oldest backup, then shift the rest up by one
+	if err := os.Remove(fmt.Sprintf("%s.%d", path, backups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := backups - 1; i >= 1; i-- {
+		if err := os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if backups < 1 {
+		return os.Remove(path)
+	}
+	return os.Rename(path, path+".1")
+}
+
+// RotatingLog is a log file that rotates itself with RotateLog once it has
+// reached maxSize, so a long session can't grow it without bound
+type RotatingLog struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+// OpenRotatingLog rotates the log file at path if it is already too large and
+// opens it for appending
+func OpenRotatingLog(path string, maxSize int64, backups int) (*RotatingLog, error) {
+	l := &RotatingLog{path: path, maxSize: maxSize, backups: backups}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *RotatingLog) open() error {
+	if err := RotateLog(l.path, l.maxSize, l.backups); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	l.file, l.size = file, info.Size()
+	return nil
+}
+
+func (l *RotatingLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if l.size >= l.maxSize {
+		l.file.Close()
+		l.file = nil
+		if err := l.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+func (l *RotatingLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"sort"
+)
+
+// RowsToGrid lays out single-line JSON rows as a grid. The headers are the
+// sorted union of top-level attribute names across all rows, and each row has
+// one cell per header. Attributes missing from a row produce empty cells and
+// nested values are shown as compact JSON. With flattenDepth above zero,
+// nested values are first expanded that many levels into columns of their
+// own, as Flatten does.
+func RowsToGrid(rows []string, flattenDepth int) ([]string, [][]string) {
+	parsed := make([]map[string]interface{}, len(rows))
+	seen := make(map[string]bool)
+
+	for i, row := range rows {
+		// Numbers are kept as written, float64 would round large ones
+		var item map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader([]byte(row)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&item); err != nil {
+			log.Printf("Failed to parse row for grid: %v", err)
+			continue
+		}
+		if flattenDepth > 0 {
+			item = Flatten(item, flattenDepth)
+		}
+		parsed[i] = item
+		for key := range item {
+			seen[key] = true
+		}
+	}
+
+	headers := make([]string, 0, len(seen))
+	for key := range seen {
+		headers = append(headers, key)
+	}
+	sort.Strings(headers)
+
+	cells := make([][]string, len(parsed))
+	for i, item := range parsed {
+		cells[i] = make([]string, len(headers))
+		for j, header := range headers {
+			value, ok := item[header]
+			if !ok {
+				continue
+			}
+			cells[i][j] = cellValue(value)
+		}
+	}
+
+	return headers, cells
+}
+
+// cellValue renders a single attribute value for display in a grid cell
+func cellValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
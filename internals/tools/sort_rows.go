@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// SortRowsByAttribute returns the single-line JSON rows ordered by the value of
+// the given top-level attribute. Values that look numeric on both sides are
+// compared as numbers, everything else lexicographically. Rows missing the
+// attribute always sort last, regardless of direction.
+func SortRowsByAttribute(rows []string, attribute string, descending bool) []string {
+	type sortableRow struct {
+		raw     string
+		value   string
+		present bool
+	}
+
+	sortable := make([]sortableRow, len(rows))
+	for i, row := range rows {
+		sortable[i].raw = row
+
+		// Numbers are kept as written, float64 would round large ones
+		var item map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader([]byte(row)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&item); err != nil {
+			continue
+		}
+		if value, ok := item[attribute]; ok && value != nil {
+			sortable[i].value = sortValue(value)
+			sortable[i].present = true
+		}
+	}
+
+	sort.SliceStable(sortable, func(i, j int) bool {
+		a, b := sortable[i], sortable[j]
+		if a.present != b.present {
+			return a.present
+		}
+		if !a.present {
+			return false
+		}
+		if descending {
+			return compareValues(b.value, a.value) < 0
+		}
+		return compareValues(a.value, b.value) < 0
+	})
+
+	sorted := make([]string, len(sortable))
+	for i, row := range sortable {
+		sorted[i] = row.raw
+	}
+	return sorted
+}
+
+// sortValue turns an attribute value into the string used for comparisons
+func sortValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	if data, err := json.Marshal(value); err == nil {
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
+// compareValues compares numerically when both values parse as numbers and
+// lexicographically otherwise. Numbers are compared exactly, as DynamoDB
+// numbers carry up to 38 digits and float64 would misorder large integers.
+func compareValues(a, b string) int {
+	numA, errA := strconv.ParseFloat(a, 64)
+	numB, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		// ParseFloat bounds the exponent, so the exact values stay small
+		exactA, okA := new(big.Rat).SetString(a)
+		exactB, okB := new(big.Rat).SetString(b)
+		if okA && okB {
+			return exactA.Cmp(exactB)
+		}
+		switch {
+		case numA < numB:
+			return -1
+		case numA > numB:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
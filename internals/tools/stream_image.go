@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	streamtypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+)
+
+// StreamImageToItem converts an item image of a DynamoDB Streams record to a
+// DynamoDB item, as the Streams API has its own copy of the attribute types
+func StreamImageToItem(image map[string]streamtypes.AttributeValue) map[string]types.AttributeValue {
+	if image == nil {
+		return nil
+	}
+
+	item := make(map[string]types.AttributeValue, len(image))
+	for name, value := range image {
+		if converted := streamAttributeValue(value); converted != nil {
+			item[name] = converted
+		}
+	}
+	return item
+}
+
+// streamAttributeValue converts one Streams attribute value, or returns nil
+// for a type it doesn't know
+func streamAttributeValue(av streamtypes.AttributeValue) types.AttributeValue {
+	switch v := av.(type) {
+	case *streamtypes.AttributeValueMemberS:
+		return &types.AttributeValueMemberS{Value: v.Value}
+	case *streamtypes.AttributeValueMemberN:
+		return &types.AttributeValueMemberN{Value: v.Value}
+	case *streamtypes.AttributeValueMemberB:
+		return &types.AttributeValueMemberB{Value: v.Value}
+	case *streamtypes.AttributeValueMemberBOOL:
+		return &types.AttributeValueMemberBOOL{Value: v.Value}
+	case *streamtypes.AttributeValueMemberNULL:
+		return &types.AttributeValueMemberNULL{Value: v.Value}
+	case *streamtypes.AttributeValueMemberSS:
+		return &types.AttributeValueMemberSS{Value: v.Value}
+	case *streamtypes.AttributeValueMemberNS:
+		return &types.AttributeValueMemberNS{Value: v.Value}
+	case *streamtypes.AttributeValueMemberBS:
+		return &types.AttributeValueMemberBS{Value: v.Value}
+	case *streamtypes.AttributeValueMemberL:
+		list := make([]types.AttributeValue, 0, len(v.Value))
+		for _, element := range v.Value {
+			if converted := streamAttributeValue(element); converted != nil {
+				list = append(list, converted)
+			}
+		}
+		return &types.AttributeValueMemberL{Value: list}
+	case *streamtypes.AttributeValueMemberM:
+		return &types.AttributeValueMemberM{Value: StreamImageToItem(v.Value)}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"sort"
+)
+
+// summaryTopValues is how many of the most common values an AttrSummary keeps
+const summaryTopValues = 3
+
+// AttrSummary describes the values one attribute takes in a sample of rows
+type AttrSummary struct {
+	Name     string
+	Count    int          // rows that have the attribute
+	Distinct int          // distinct values
+	Top      []ValueCount // most common values, the most common first
+	Numeric  bool         // every value is a number, so Min and Max are set
+	Min, Max float64
+}
+
+// ValueCount is a value, written as JSON, with the number of rows that have it
+type ValueCount struct {
+	Value string
+	Count int
+}
+
+// SummarizeAttributes counts the values of every top-level attribute of
+// single-line JSON rows. Attributes are ordered like InferSchema orders them:
+// by how many rows have them, then by name.
+func SummarizeAttributes(rows []string) []AttrSummary {
+	values := make(map[string]map[string]int)
+	summaries := make(map[string]*AttrSummary)
+
+	for _, row := range rows {
+		decoder := json.NewDecoder(bytes.NewReader([]byte(row)))
+		decoder.UseNumber()
+		var item map[string]interface{}
+		if err := decoder.Decode(&item); err != nil {
+			log.Printf("Failed to parse row for summary: %v", err)
+			continue
+		}
+
+		for name, value := range item {
+			summary, ok := summaries[name]
+			if !ok {
+				summary = &AttrSummary{Name: name, Numeric: true}
+				summaries[name] = summary
+				values[name] = make(map[string]int)
+			}
+			summary.Count++
+			values[name][summaryValue(value)]++
+
+			number, isNumber := value.(json.Number)
+			f, err := number.Float64()
+			if !isNumber || err != nil {
+				summary.Numeric = false
+				continue
+			}
+			if summary.Count == 1 || f < summary.Min {
+				summary.Min = f
+			}
+			if summary.Count == 1 || f > summary.Max {
+				summary.Max = f
+			}
+		}
+	}
+
+	result := make([]AttrSummary, 0, len(summaries))
+	for name, summary := range summaries {
+		summary.Distinct = len(values[name])
+		summary.Top = topValues(values[name], summaryTopValues)
+		if !summary.Numeric {
+			summary.Min, summary.Max = 0, 0
+		}
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// summaryValue writes a decoded JSON value the way SummarizeAttributes counts it
+func summaryValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "?"
+	}
+	return string(data)
+}
+
+// topValues returns the n most common values, ties in value order
+func topValues(counts map[string]int, n int) []ValueCount {
+	top := make([]ValueCount, 0, len(counts))
+	for value, count := range counts {
+		top = append(top, ValueCount{Value: value, Count: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Value < top[j].Value
+	})
+	return top[:min(n, len(top))]
+}
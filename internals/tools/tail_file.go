@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// TailFile returns up to the last maxBytes of the file at path. When the file
+// is larger than that, the first partial line is dropped so the result always
+// starts at a line boundary.
+func TailFile(path string, maxBytes int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	offset := max(0, info.Size()-maxBytes)
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	content := string(data)
+	if offset > 0 {
+		if i := strings.IndexByte(content, '\n'); i >= 0 {
+			content = content[i+1:]
+		}
+	}
+
+	return content, nil
+}
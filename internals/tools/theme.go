@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Theme holds the colors of the interface. Each color is an ANSI number such
+// as "10" or a hex color such as "#7aa2f7".
+type Theme struct {
+	// ActiveBorder colors the border and label of the focused pane, and the
+	// dialogs
+	ActiveBorder string `json:"activeBorder,omitempty"`
+	// DefaultBorder colors the borders of the other panes
+	DefaultBorder string `json:"defaultBorder,omitempty"`
+	// SelectedItem colors the selected table and grid row
+	SelectedItem string `json:"selectedItem,omitempty"`
+	// Spinner colors the loading indicators and the prompt
+	Spinner string `json:"spinner,omitempty"`
+	// HelpKey and HelpDescription color the keys and their descriptions in
+	// the help line. Empty keeps colors that adapt to the terminal background.
+	HelpKey         string `json:"helpKey,omitempty"`
+	HelpDescription string `json:"helpDescription,omitempty"`
+	// StatusBarForeground and StatusBarBackground color the status bar
+	StatusBarForeground string `json:"statusBarForeground,omitempty"`
+	StatusBarBackground string `json:"statusBarBackground,omitempty"`
+	// Mode is the background of the status bar's mode badge
+	Mode string `json:"mode,omitempty"`
+	// Error is the background of the error banner and the read-only badge
+	Error string `json:"error,omitempty"`
+}
+
+// DefaultThemeName is the built-in theme used when none is configured
+const DefaultThemeName = "dark"
+
+// builtinThemes are the themes that can be picked by name
+var builtinThemes = map[string]Theme{
+	"dark": {
+		ActiveBorder:        "10",
+		DefaultBorder:       "#ffffff",
+		SelectedItem:        "10",
+		Spinner:             "10",
+		StatusBarForeground: "252",
+		StatusBarBackground: "236",
+		Mode:                "10",
+		Error:               "1",
+	},
+	"light": {
+		ActiveBorder:        "28",
+		DefaultBorder:       "240",
+		SelectedItem:        "28",
+		Spinner:             "28",
+		HelpKey:             "240",
+		HelpDescription:     "245",
+		StatusBarForeground: "235",
+		StatusBarBackground: "254",
+		Mode:                "28",
+		Error:               "160",
+	},
+	"solarized": {
+		ActiveBorder:        "#859900",
+		DefaultBorder:       "#93a1a1",
+		SelectedItem:        "#268bd2",
+		Spinner:             "#b58900",
+		HelpKey:             "#839496",
+		HelpDescription:     "#586e75",
+		StatusBarForeground: "#eee8d5",
+		StatusBarBackground: "#073642",
+		Mode:                "#2aa198",
+		Error:               "#dc322f",
+	},
+}
+
+// ThemeNames lists the built-in themes in alphabetical order
+func ThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTheme returns the built-in theme called name, empty for the default
+// one. With a path, the theme file is read on top of it, so the file only
+// needs the colors it changes.
+func LoadTheme(name, path string) (Theme, error) {
+	if name == "" {
+		name = DefaultThemeName
+	}
+	theme, ok := builtinThemes[strings.ToLower(name)]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q, choose one of %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	if path == "" {
+		return theme, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return Theme{}, fmt.Errorf("invalid theme file %s: %w", path, err)
+	}
+
+	return theme, nil
+}
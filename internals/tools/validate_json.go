@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidateJSON checks that raw is valid JSON. A syntax error is reported with
+// its line and column, e.g. "line 3, column 12: invalid character '}'".
+func ValidateJSON(raw string) error {
+	if json.Valid([]byte(raw)) {
+		return nil
+	}
+
+	var value interface{}
+	err := json.Unmarshal([]byte(raw), &value)
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	line, column := textPosition(raw, int(syntaxErr.Offset))
+	return fmt.Errorf("line %d, column %d: %s", line, column, syntaxErr.Error())
+}
+
+// textPosition turns the byte offset the decoder reports, just past the
+// offending character, into a 1-based line and column
+func textPosition(text string, offset int) (line, column int) {
+	index := min(max(offset-1, 0), len(text))
+	before := text[:index]
+	line = strings.Count(before, "\n") + 1
+	column = index - strings.LastIndex(before, "\n")
+	return line, column
+}
@@ -0,0 +1,99 @@
+package lazydynamo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// ssoErrorHints are fragments of error messages the SDK produces when an SSO
+// session or its cached token is no longer usable
+var ssoErrorHints = []string{
+	"sso session has expired",
+	"refresh cached sso token",
+	"failed to read cached sso token",
+	"sso oidc",
+}
+
+// credentialErrorCodes are AWS error codes returned for expired or unusable
+// credentials
+var credentialErrorCodes = map[string]bool{
+	"ExpiredToken":                true,
+	"ExpiredTokenException":       true,
+	"InvalidClientTokenId":        true,
+	"UnrecognizedClientException": true,
+}
+
+// accessDeniedErrorCodes are AWS error codes returned when an IAM policy
+// doesn't allow the action
+var accessDeniedErrorCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+}
+
+// friendlyAWSError turns credential and SSO failures into actionable messages.
+// Other errors are returned unchanged.
+func friendlyAWSError(err error, profile string) error {
+	if isSSOSessionError(err) {
+		return fmt.Errorf("SSO session expired — run `aws sso login --profile %s`, then press ctrl+k to reconnect: %w", profile, err)
+	}
+
+	if isCredentialError(err) {
+		return fmt.Errorf("AWS credentials for profile %s are expired or invalid — refresh them, then press ctrl+k to reconnect: %w", profile, err)
+	}
+
+	return err
+}
+
+// reconnectableError marks a failed AWS call, which reconnecting may fix
+type reconnectableError struct{ error }
+
+func (e reconnectableError) Unwrap() error { return e.error }
+
+// fetchError describes a failed AWS call for the user, marking it
+// reconnectable only when it failed on credentials or the connection. Errors
+// such as a missing table or a throttled request aren't fixed by reconnecting.
+func fetchError(err error, profile string) error {
+	friendly := friendlyAWSError(err, profile)
+	if isSSOSessionError(err) || isCredentialError(err) || isTransportError(err) {
+		return reconnectableError{friendly}
+	}
+	return friendly
+}
+
+// isAccessDenied reports whether the caller's policies don't allow the call
+func isAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && accessDeniedErrorCodes[apiErr.ErrorCode()]
+}
+
+func isSSOSessionError(err error) bool {
+	var tokenErr *ssocreds.InvalidTokenError
+	return errors.As(err, &tokenErr) || isSSOError(err)
+}
+
+func isCredentialError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && credentialErrorCodes[apiErr.ErrorCode()]
+}
+
+// isTransportError reports whether the request never got a response, e.g.
+// because the network or the endpoint was unreachable
+func isTransportError(err error) bool {
+	var sendErr *smithyhttp.RequestSendError
+	return errors.As(err, &sendErr)
+}
+
+func isSSOError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, hint := range ssoErrorHints {
+		if strings.Contains(message, hint) {
+			return true
+		}
+	}
+	return false
+}
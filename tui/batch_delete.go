@@ -0,0 +1,152 @@
+package lazydynamo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// batchWriteSize is the most requests DynamoDB accepts per BatchWriteItem
+	batchWriteSize = 25
+	// maxBatchRetries bounds how often unprocessed items are retried
+	maxBatchRetries = 5
+
+	batchDeleteConfirmID = "batch-delete"
+)
+
+// RowsDeletedMsg reports the outcome of a batch delete
+type RowsDeletedMsg struct {
+	region  string
+	table   string
+	deleted []string
+	err     error
+}
+
+// deleteRows removes the given rows from the table with BatchWriteItem, in
+// chunks of 25 and retrying unprocessed items with backoff
+func (m TableDataModel) deleteRows(tableName string, rows []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			return RowsDeletedMsg{region: m.region, table: tableName, err: err}
+		}
+
+		var deleted []string
+		for start := 0; start < len(rows); start += batchWriteSize {
+			chunk := rows[start:min(start+batchWriteSize, len(rows))]
+
+			var requests []types.WriteRequest
+			var chunkRows []string
+			for _, row := range chunk {
+				key, err := rowKey(row, tableInfo)
+				if err != nil {
+					log.Printf("Skipping row without a usable key: %v", err)
+					continue
+				}
+				requests = append(requests, types.WriteRequest{
+					DeleteRequest: &types.DeleteRequest{Key: key},
+				})
+				chunkRows = append(chunkRows, row)
+			}
+			if len(requests) == 0 {
+				continue
+			}
+
+			if err := batchWrite(ctx, m.client, tableName, requests); err != nil {
+				invalidateTableDataCache(m.region, tableName)
+				return RowsDeletedMsg{region: m.region, table: tableName, deleted: deleted, err: err}
+			}
+			deleted = append(deleted, chunkRows...)
+		}
+
+		invalidateTableDataCache(m.region, tableName)
+
+		var skipErr error
+		if skipped := len(rows) - len(deleted); skipped > 0 {
+			skipErr = fmt.Errorf("%d rows were skipped because their key couldn't be built", skipped)
+		}
+		return RowsDeletedMsg{region: m.region, table: tableName, deleted: deleted, err: skipErr}
+	}
+}
+
+// batchWrite sends the write requests, retrying unprocessed items until they
+// all go through or the retries run out
+func batchWrite(ctx context.Context, client *dynamodb.Client, tableName string, requests []types.WriteRequest) error {
+	pending := map[string][]types.WriteRequest{tableName: requests}
+
+	for attempt := 0; attempt <= maxBatchRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(1<<attempt) * 100 * time.Millisecond):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		output, err := client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: pending,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(output.UnprocessedItems) == 0 {
+			return nil
+		}
+		pending = output.UnprocessedItems
+	}
+
+	return fmt.Errorf("%d items were still unprocessed after %d retries", len(pending[tableName]), maxBatchRetries)
+}
+
+// rowKey extracts the primary key of a data row
+func rowKey(row string, tableInfo *types.TableDescription) (map[string]types.AttributeValue, error) {
+	obj, err := tools.ParseItemJSON(row)
+	if err != nil {
+		return nil, err
+	}
+	return tools.ExtractItemKey(obj, tableInfo)
+}
+
+// replaceRow swaps a row of the data list for its updated version
+func (m *TableDataModel) replaceRow(old, updated string) tea.Cmd {
+	for i, item := range m.dataList.Items() {
+		if item.FilterValue() == old {
+			return m.dataList.SetItem(i, tableDataRow(updated))
+		}
+	}
+	return m.dataList.InsertItem(len(m.dataList.Items()), tableDataRow(updated))
+}
+
+// removeRows drops the given rows from the data list
+func (m *TableDataModel) removeRows(rows []string) tea.Cmd {
+	removed := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		removed[row] = true
+	}
+
+	var items []list.Item
+	for _, item := range m.dataList.Items() {
+		if !removed[item.FilterValue()] {
+			items = append(items, item)
+		}
+	}
+
+	cmd := m.dataList.SetItems(items)
+	if m.showGrid {
+		m.refreshGrid()
+	}
+	return cmd
+}
@@ -0,0 +1,60 @@
+package lazydynamo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+)
+
+// scanCLICommand builds the AWS CLI command that performs the same scan as
+// the one shown for the selected table. The CLI can't assume a role per
+// command, so with roleArn set the command is preceded by a comment saying
+// which role it needs.
+func (m TableDataModel) scanCLICommand(profile, roleArn string) string {
+	args := []string{
+		"aws", "dynamodb", "scan",
+		"--table-name", shellQuote(m.selectedTable),
+		"--region", shellQuote(m.region),
+	}
+	if m.scanPageLimit != 0 {
+		args = append(args, "--page-size", fmt.Sprint(m.scanPageLimit))
+	}
+	if m.scanIndex != "" {
+		args = append(args, "--index-name", shellQuote(m.scanIndex))
+	}
+	if m.scanFilter != nil {
+		args = append(args, "--filter-expression", shellQuote(m.scanFilter.Expression))
+		if names, err := json.Marshal(m.scanFilter.Names); err == nil {
+			args = append(args, "--expression-attribute-names", shellQuote(string(names)))
+		}
+		if len(m.scanFilter.Values) > 0 {
+			if values, err := tools.MarshalTypedJSON(m.scanFilter.Values); err == nil {
+				args = append(args, "--expression-attribute-values", shellQuote(values))
+			}
+		}
+	}
+	if profile != "" && profile != "default" {
+		args = append(args, "--profile", shellQuote(profile))
+	}
+
+	command := strings.Join(args, " ")
+	if roleArn != "" {
+		// A comment line keeps the copied text runnable as is
+		command = fmt.Sprintf("# run as role %s, e.g. with a profile that sets role_arn and source_profile\n%s", roleArn, command)
+	}
+	return command
+}
+
+// shellQuote quotes value for a POSIX shell when it contains anything besides
+// characters that are always safe
+func shellQuote(value string) string {
+	safe := value != "" && strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@", r))
+	}) == -1
+	if safe {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,47 @@
+package lazydynamo
+
+import (
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// collectionsFilterMode is how the collections list filter matches tables
+type collectionsFilterMode int
+
+const (
+	fuzzyFilter collectionsFilterMode = iota
+	prefixFilter
+	subsequenceFilter
+	rankedFilter
+)
+
+func (f collectionsFilterMode) String() string {
+	switch f {
+	case prefixFilter:
+		return "prefix"
+	case subsequenceFilter:
+		return "subsequence"
+	case rankedFilter:
+		return "ranked"
+	default:
+		return "fuzzy"
+	}
+}
+
+// next returns the mode that follows f when cycling through them
+func (f collectionsFilterMode) next() collectionsFilterMode {
+	return (f + 1) % (rankedFilter + 1)
+}
+
+func (f collectionsFilterMode) filter() list.FilterFunc {
+	switch f {
+	case prefixFilter:
+		return tools.PrefixFilter
+	case subsequenceFilter:
+		return tools.FilterTables
+	case rankedFilter:
+		return tools.RankedFilter
+	default:
+		return list.DefaultFilter
+	}
+}
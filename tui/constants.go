@@ -4,20 +4,42 @@ import (
 	"os"
 	"path/filepath"
 	"time"
-
-	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	BoxActiveColor             = lipgloss.Color("10")
-	BoxDefaultColor            = lipgloss.Color("#ffffff")
 	useHighPerformanceRenderer = true
+
+	// DefaultRegion is used when neither the flags, the config file, the
+	// environment nor the AWS profile name a region
+	DefaultRegion = "us-east-1"
+
+	// Smallest terminal the layout is drawn in
+	minTerminalWidth  = 60
+	minTerminalHeight = 20
 )
 
 var (
-	CacheDir                 = filepath.Join(os.Getenv("HOME"), ".lazydynamo_cache")
-	CollectionsCacheFilePath = filepath.Join(CacheDir, "collections_cache.json")
-	CacheDuration            = 72 * time.Hour // Cache expiry duration
+	CacheDir       = defaultCacheDir()
+	ConfigFilePath = filepath.Join(CacheDir, "config.json")
+	// PinnedTablesFilePath keeps the tables pinned to the top of the list
+	PinnedTablesFilePath = filepath.Join(CacheDir, "pinned_tables.json")
+	// PromptHistoryFilePath keeps what was entered in the prompts
+	PromptHistoryFilePath = filepath.Join(CacheDir, "prompt_history.json")
+	CacheDuration         = 72 * time.Hour // Cache expiry duration
+	LogFilePath           string           // Set by the entrypoint to the active debug log
 )
 
 type FetchErrorMsg struct{ error }
+
+// defaultCacheDir returns ~/.lazydynamo_cache. Without a home directory, e.g.
+// when HOME is unset in CI, it falls back to the platform's cache directory
+// and finally to the temp directory, so the path is never relative.
+func defaultCacheDir() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".lazydynamo_cache")
+	}
+	if cache, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(cache, "lazydynamo")
+	}
+	return filepath.Join(os.TempDir(), "lazydynamo_cache")
+}
@@ -0,0 +1,60 @@
+package lazydynamo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// AccountIDFetchedMsg carries the AWS account ID looked up to build the ARN
+// of table
+type AccountIDFetchedMsg struct {
+	account string
+	region  string
+	table   string
+}
+
+// copyText copies text to the clipboard and reports the outcome in the status
+// bar
+func (m *MainModel) copyText(text, what string) {
+	if err := tools.CopyToClipboard(text); err != nil {
+		log.Println("Failed to copy to clipboard:", err)
+		m.lastErr = fmt.Errorf("couldn't copy %s: %w", what, err)
+		return
+	}
+	m.notice = "Copied " + what
+}
+
+// copyTableArn copies the ARN of a table in region. The account ID is looked
+// up through STS the first time and reused for the rest of the session.
+func (m *MainModel) copyTableArn(region, table string) tea.Cmd {
+	if m.accountID != "" {
+		m.copyText(tableArn(region, m.accountID, table), "table ARN")
+		return nil
+	}
+
+	cfg := m.awsConfig.Copy()
+	cfg.Region = region
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			return FetchErrorMsg{err}
+		}
+
+		return AccountIDFetchedMsg{account: aws.ToString(identity.Account), region: region, table: table}
+	}
+}
+
+// tableArn builds the ARN of a DynamoDB table
+func tableArn(region, account, table string) string {
+	return fmt.Sprintf("arn:aws:dynamodb:%s:%s:table/%s", region, account, table)
+}
@@ -0,0 +1,81 @@
+package lazydynamo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const deleteTableConfirmID = "delete-table"
+
+// TableDeletedMsg reports the outcome of deleting a table
+type TableDeletedMsg struct {
+	region string
+	table  string
+	err    error
+}
+
+// pendingTable is a table waiting for the delete confirmation
+type pendingTable struct {
+	region string
+	table  string
+}
+
+// confirmDeleteTable asks the user to type the table's name before deleting it
+func (m *MainModel) confirmDeleteTable(item tableNameItem) {
+	region, table := m.splitCollectionItem(item)
+	m.pendingTableDelete = &pendingTable{region: region, table: table}
+
+	message := fmt.Sprintf("Delete table %s in %s?\nAll of its items are lost. This can't be undone.\n\nType the table name to confirm.", table, region)
+	m.confirmDialog = m.confirmDialog.OpenTyped(deleteTableConfirmID, message, table)
+}
+
+// deleteTable deletes a table through the client of its region
+func (m MainModel) deleteTable(region, table string) tea.Cmd {
+	client := m.clientFor(region)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		_, err := client.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: &table})
+		if err != nil {
+			return TableDeletedMsg{region: region, table: table, err: err}
+		}
+
+		invalidateTableDataCache(region, table)
+		if err := os.Remove(collectionsCacheFilePath(region)); err != nil && !os.IsNotExist(err) {
+			log.Println("Failed to remove collections cache:", err)
+		}
+
+		return TableDeletedMsg{region: region, table: table}
+	}
+}
+
+// removeCollection drops a deleted table from the collections list and, if it
+// was selected, from the data pane
+func (m *MainModel) removeCollection(region, table string) tea.Cmd {
+	removed := m.collectionItem(region, table)
+
+	var items []list.Item
+	for _, item := range m.collectionsList.Items() {
+		if item != removed {
+			items = append(items, item)
+		}
+	}
+	cmds := []tea.Cmd{m.collectionsList.SetItems(items)}
+
+	if m.tableDataModel.region == region && m.tableDataModel.selectedTable == table {
+		m.tableDataModel.stopScan()
+		m.tableDataModel.selectTable(m.tableDataModel.client, region, "")
+		m.tableDataModel.dataLoaded = false
+		cmds = append(cmds, m.tableDataModel.dataList.SetItems(nil))
+	}
+
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,95 @@
+package lazydynamo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// describeCacheTTL is how long table metadata is reused before describing the
+// table again
+const describeCacheTTL = 5 * time.Minute
+
+type describeCacheEntry struct {
+	table   *types.TableDescription
+	fetched time.Time
+}
+
+// describeCache keeps DescribeTable results per table. It is shared by pointer
+// between model copies and is safe to use from fetch goroutines.
+type describeCache struct {
+	mu      sync.Mutex
+	entries map[string]describeCacheEntry
+}
+
+func newDescribeCache() *describeCache {
+	return &describeCache{entries: make(map[string]describeCacheEntry)}
+}
+
+func (c *describeCache) get(tableName string) (*types.TableDescription, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[tableName]
+	if !ok || time.Since(entry.fetched) > describeCacheTTL {
+		return nil, false
+	}
+	return entry.table, true
+}
+
+func (c *describeCache) put(tableName string, table *types.TableDescription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[tableName] = describeCacheEntry{table: table, fetched: time.Now()}
+}
+
+func (c *describeCache) invalidate(tableName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, tableName)
+}
+
+// describeTable returns the table's metadata, describing it only when there
+// is no fresh cached copy
+func (m TableDataModel) describeTable(ctx context.Context, tableName string) (*types.TableDescription, error) {
+	if table, ok := m.tableInfo.get(tableName); ok {
+		return table, nil
+	}
+
+	output, err := m.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: &tableName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Tables that aren't ready yet are described again next time, so their
+	// status is picked up as soon as it changes
+	if scannable(output.Table) {
+		m.tableInfo.put(tableName, output.Table)
+	}
+
+	return output.Table, nil
+}
+
+// scannable reports whether the table can be read. Tables being updated
+// still serve reads; tables being created, deleted or archived don't.
+func scannable(table *types.TableDescription) bool {
+	switch table.TableStatus {
+	case types.TableStatusActive, types.TableStatusUpdating:
+		return true
+	}
+	return false
+}
+
+// tableNotReadyError explains why a table that isn't scannable can't be read
+func tableNotReadyError(table *types.TableDescription) error {
+	return fmt.Errorf("table is %s, cannot scan yet", strings.ToLower(string(table.TableStatus)))
+}
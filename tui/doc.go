@@ -0,0 +1,4 @@
+// Package lazydynamo implements the terminal UI: MainModel drives the panes,
+// and TableDataModel, ViewRowModel and the other models each own one of
+// them. It is the only package holding this code.
+package lazydynamo
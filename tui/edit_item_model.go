@@ -0,0 +1,294 @@
+package lazydynamo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NewItemTemplateMsg carries the skeleton JSON used to pre-fill the editor
+type NewItemTemplateMsg struct {
+	partitionKey string
+	sortKey      *string
+	template     string
+}
+
+// ItemSavedMsg is sent once an item was written to the table
+type ItemSavedMsg struct {
+	region   string
+	table    string
+	row      string
+	replaces string // row the saved item replaces in the list, if any
+}
+
+// ItemSaveFailedMsg is sent when validation or the PutItem call failed
+type ItemSaveFailedMsg struct{ error }
+
+type EditItemKeyMap struct {
+	Save   key.Binding
+	Cancel key.Binding
+}
+
+func (k EditItemKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Save, k.Cancel}
+}
+
+func (k EditItemKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Save, k.Cancel},
+	}
+}
+
+var editItemKeys = EditItemKeyMap{
+	Save: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("ctrl+s", "save item"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
+}
+
+var (
+	editorErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	editorValidStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+)
+
+type EditItemModel struct {
+	keys         EditItemKeyMap
+	editor       textarea.Model
+	partitionKey string
+	sortKey      *string
+	err          error
+	syntaxErr    error // the editor content isn't valid JSON, checked as it is typed
+}
+
+func (m EditItemModel) New() EditItemModel {
+	ta := textarea.New()
+	ta.ShowLineNumbers = true
+	ta.CharLimit = 0
+	ta.Placeholder = "{}"
+
+	return EditItemModel{
+		keys:   editItemKeys,
+		editor: ta,
+	}
+}
+
+// open pre-fills the editor with the given template and focuses it
+func (m *EditItemModel) open(msg NewItemTemplateMsg) tea.Cmd {
+	m.partitionKey = msg.partitionKey
+	m.sortKey = msg.sortKey
+	m.err = nil
+	m.editor.SetValue(msg.template)
+	m.checkSyntax()
+
+	return m.editor.Focus()
+}
+
+// update passes input to the editor and checks the JSON again, so save is
+// only offered while it parses
+func (m *EditItemModel) update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	m.checkSyntax()
+	return cmd
+}
+
+func (m *EditItemModel) checkSyntax() {
+	m.syntaxErr = tools.ValidateJSON(m.editor.Value())
+	m.keys.Save.SetEnabled(m.syntaxErr == nil)
+}
+
+func (m *EditItemModel) close() {
+	m.err = nil
+	m.editor.Blur()
+	m.editor.Reset()
+}
+
+// validate parses the editor content and checks the key attributes are set
+func (m EditItemModel) validate() (map[string]interface{}, error) {
+	obj, err := tools.ParseItemJSON(m.editor.Value())
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	keyNames := []string{m.partitionKey}
+	if m.sortKey != nil {
+		keyNames = append(keyNames, *m.sortKey)
+	}
+
+	for _, name := range keyNames {
+		value, ok := obj[name]
+		if !ok || value == nil || value == "" {
+			return nil, fmt.Errorf("key attribute %q must be present and non-empty", name)
+		}
+	}
+
+	return obj, nil
+}
+
+func (m EditItemModel) View() string {
+	view := m.editor.View()
+	if m.syntaxErr != nil {
+		view += "\n" + editorErrorStyle.Render("✗ "+m.syntaxErr.Error())
+	} else {
+		view += "\n" + editorValidStyle.Render("✓ valid JSON")
+	}
+	if m.err != nil {
+		view += "\n" + editorErrorStyle.Render(m.err.Error())
+	}
+	return view
+}
+
+// fetchNewItemTemplate describes the table and builds a skeleton item holding
+// its key attributes with empty placeholder values
+func (m TableDataModel) fetchNewItemTemplate(tableName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			log.Printf("Failed to describe table: %v", err)
+			return FetchErrorMsg{err}
+		}
+
+		partitionKey, sortKey, err := extractPrimaryKeyAttributes(tableInfo.KeySchema)
+		if err != nil {
+			log.Printf("Failed to retrieve primary key schema: %v", err)
+			return FetchErrorMsg{err}
+		}
+
+		attributeTypes := make(map[string]types.ScalarAttributeType)
+		for _, definition := range tableInfo.AttributeDefinitions {
+			attributeTypes[*definition.AttributeName] = definition.AttributeType
+		}
+
+		skeleton := map[string]interface{}{
+			partitionKey: placeholderFor(attributeTypes[partitionKey]),
+		}
+		if sortKey != nil {
+			skeleton[*sortKey] = placeholderFor(attributeTypes[*sortKey])
+		}
+
+		template, err := json.MarshalIndent(skeleton, "", "  ")
+		if err != nil {
+			return FetchErrorMsg{err}
+		}
+
+		return NewItemTemplateMsg{
+			partitionKey: partitionKey,
+			sortKey:      sortKey,
+			template:     string(template),
+		}
+	}
+}
+
+// placeholderFor returns an empty value for a key: "" for strings and null
+// for numbers, both of which validate refuses until they are filled in
+func placeholderFor(attributeType types.ScalarAttributeType) interface{} {
+	if attributeType == types.ScalarAttributeTypeN {
+		return nil
+	}
+	return ""
+}
+
+// errItemExists replaces the ConditionalCheckFailed of a put meant to create
+// a new item
+var errItemExists = errors.New("an item with this key already exists")
+
+// putItemInput builds the PutItem request for item. A new item must not
+// overwrite an existing one, so it is only put when nothing holds its key yet.
+func putItemInput(tableName string, item map[string]types.AttributeValue, tableInfo *types.TableDescription, isNew bool) *dynamodb.PutItemInput {
+	input := &dynamodb.PutItemInput{
+		TableName: &tableName,
+		Item:      item,
+	}
+	if isNew && tableInfo != nil && len(tableInfo.KeySchema) > 0 {
+		input.ConditionExpression = aws.String("attribute_not_exists(#pk)")
+		input.ExpressionAttributeNames = map[string]string{"#pk": *tableInfo.KeySchema[0].AttributeName}
+	}
+	return input
+}
+
+// putItem writes the item to the table and returns it as a data row. When
+// the item is an edited version of the row replaces, it takes that row's
+// place in the list, unless its key was changed and it became a new item.
+// New items, including edits with a changed key, never overwrite an
+// existing item.
+func (m TableDataModel) putItem(tableName string, obj map[string]interface{}, replaces string) tea.Cmd {
+	return func() tea.Msg {
+		item, err := tools.MapToDynamoItem(obj)
+		if err != nil {
+			return ItemSaveFailedMsg{err}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			return ItemSaveFailedMsg{err}
+		}
+
+		// Binary keys are kept whole in the row, so it can be written to
+		mapItem, err := tools.DynamoItemToRowMap(item, tools.KeyAttributes(tableInfo))
+		if err != nil {
+			return ItemSaveFailedMsg{err}
+		}
+		row, err := json.Marshal(mapItem)
+		if err != nil {
+			return ItemSaveFailedMsg{err}
+		}
+
+		if replaces != "" && !sameKey(tableInfo, replaces, string(row)) {
+			replaces = ""
+		}
+
+		if _, err := m.client.PutItem(ctx, putItemInput(tableName, item, tableInfo, replaces == "")); err != nil {
+			log.Printf("Failed to put item: %v", err)
+			var conditionFailed *types.ConditionalCheckFailedException
+			if errors.As(err, &conditionFailed) {
+				err = errItemExists
+			}
+			return ItemSaveFailedMsg{err}
+		}
+
+		invalidateTableDataCache(m.region, tableName)
+
+		return ItemSavedMsg{region: m.region, table: tableName, row: string(row), replaces: replaces}
+	}
+}
+
+// sameKey reports whether two rows have the same primary key
+func sameKey(tableInfo *types.TableDescription, a, b string) bool {
+	keyA, errA := rowKey(a, tableInfo)
+	keyB, errB := rowKey(b, tableInfo)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return reflect.DeepEqual(keyA, keyB)
+}
+
+// invalidateTableDataCache drops the cached scan of a table after it was modified
+func invalidateTableDataCache(region, tableName string) {
+	if err := tools.RemoveCache(tableDataCacheFilePath(region, tableName)); err != nil {
+		log.Println("Failed to invalidate cache:", err)
+	}
+}
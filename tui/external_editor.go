@@ -0,0 +1,164 @@
+package lazydynamo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EditorClosedMsg is sent when the external editor exits
+type EditorClosedMsg struct {
+	path     string
+	row      string // row that was opened in the editor
+	original string // file content before editing
+	err      error
+}
+
+// EditableItemMsg carries the current version of a row's item, checked to
+// survive a round trip through plain JSON
+type EditableItemMsg struct {
+	row  string // row the item was read for
+	item string // the item as plain JSON
+	err  error
+}
+
+// pendingEdit is an item edited in the external editor, waiting for the
+// save confirmation
+type pendingEdit struct {
+	row string
+	obj map[string]interface{}
+}
+
+// editorCommand returns the user's editor, falling back to a platform default
+// when neither $VISUAL nor $EDITOR is set
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// fetchEditableItem reads the row's item back from DynamoDB before it is
+// edited. The list rows are display JSON, where binaries are only a preview
+// and sets look like lists, so putting them back would silently corrupt those
+// attributes; items holding them are refused.
+func (m TableDataModel) fetchEditableItem(tableName, row string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			return EditableItemMsg{row: row, err: err}
+		}
+
+		key, err := rowKey(row, tableInfo)
+		if err != nil {
+			return EditableItemMsg{row: row, err: err}
+		}
+
+		output, err := m.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName:      &tableName,
+			Key:            key,
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return EditableItemMsg{row: row, err: err}
+		}
+		if output.Item == nil {
+			return EditableItemMsg{row: row, err: fmt.Errorf("item no longer exists in %s", tableName)}
+		}
+
+		if path, lossy := tools.FindLossyAttribute(output.Item); lossy {
+			return EditableItemMsg{row: row, err: fmt.Errorf("%s is a binary or set value that plain JSON can't keep — press u to update single attributes instead", path)}
+		}
+
+		mapItem, err := tools.DynamoItemToRowMap(output.Item, tools.KeyAttributes(tableInfo))
+		if err != nil {
+			return EditableItemMsg{row: row, err: err}
+		}
+		item, err := json.Marshal(mapItem)
+		if err != nil {
+			return EditableItemMsg{row: row, err: err}
+		}
+
+		return EditableItemMsg{row: row, item: string(item)}
+	}
+}
+
+// openInEditor writes the item as indented JSON to a temp file and suspends
+// the UI while the user's editor has it open. row is the list row the saved
+// item replaces.
+func openInEditor(row, item string) tea.Cmd {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, []byte(item), "", "  "); err != nil {
+		return func() tea.Msg { return FetchErrorMsg{err} }
+	}
+	pretty.WriteString("\n")
+
+	file, err := os.CreateTemp("", "lazydynamo-item-*.json")
+	if err != nil {
+		return func() tea.Msg { return FetchErrorMsg{err} }
+	}
+	defer file.Close()
+
+	if _, err := file.Write(pretty.Bytes()); err != nil {
+		os.Remove(file.Name())
+		return func() tea.Msg { return FetchErrorMsg{err} }
+	}
+
+	editor := editorCommand()
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	msg := EditorClosedMsg{path: file.Name(), row: row, original: pretty.String()}
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		msg.err = err
+		return msg
+	})
+}
+
+// handleEditorClosed reads the edited file back and asks to save it if the
+// item changed
+func (m *MainModel) handleEditorClosed(msg EditorClosedMsg) {
+	defer os.Remove(msg.path)
+
+	if msg.err != nil {
+		m.lastErr = fmt.Errorf("editor exited with an error: %w", msg.err)
+		return
+	}
+
+	edited, err := os.ReadFile(msg.path)
+	if err != nil {
+		m.lastErr = err
+		return
+	}
+	if string(edited) == msg.original {
+		m.notice = "No changes"
+		return
+	}
+
+	obj, err := tools.ParseItemJSON(string(edited))
+	if err != nil {
+		m.lastErr = fmt.Errorf("edited item isn't valid JSON: %w", err)
+		return
+	}
+
+	m.confirmPut(msg.row, obj, fmt.Sprintf("Save the edited item to %s?", m.tableDataModel.selectedTable))
+}
@@ -0,0 +1,43 @@
+package lazydynamo
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// FilteringKeyMap is the legend shown while a list filter is being typed.
+// The filter input takes every other key, so only these apply.
+type FilteringKeyMap struct {
+	Type   key.Binding
+	Accept key.Binding
+	Cancel key.Binding
+}
+
+func (k FilteringKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Type, k.Accept, k.Cancel}
+}
+
+func (k FilteringKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// typeToFilter is only shown in the legend; typed characters go to the filter
+// input rather than being matched
+var typeToFilter = key.NewBinding(
+	key.WithKeys("type"),
+	key.WithHelp("type", "to filter"),
+)
+
+// legendFor returns the legend of a pane whose list is l: keys, or the filter
+// keys while the filter is being typed
+func legendFor(l list.Model, keys help.KeyMap) help.KeyMap {
+	if l.FilterState() != list.Filtering {
+		return keys
+	}
+	return FilteringKeyMap{
+		Type:   typeToFilter,
+		Accept: l.KeyMap.AcceptWhileFiltering,
+		Cancel: l.KeyMap.CancelWhileFiltering,
+	}
+}
@@ -0,0 +1,182 @@
+package lazydynamo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyAttribute is a key attribute of a table with its declared type
+type keyAttribute struct {
+	name     string
+	attrType types.ScalarAttributeType
+}
+
+// KeySchemaLoadedMsg carries the key attributes of a table, partition key
+// first, to prompt for an item's key
+type KeySchemaLoadedMsg struct {
+	region string
+	table  string
+	keys   []keyAttribute
+	query  bool // the keys are for a query rather than a GetItem
+	err    error
+}
+
+// ItemFetchedMsg carries an item looked up by its key. found is false when
+// no item has the key.
+type ItemFetchedMsg struct {
+	region string
+	table  string
+	key    string // the key as entered, e.g. "id=42"
+	row    string
+	found  bool
+	err    error
+}
+
+// pendingGetItem is a key being entered, one attribute per prompt
+type pendingGetItem struct {
+	table  string
+	keys   []keyAttribute
+	values []string
+}
+
+// loadKeySchema describes the table to learn which key attributes to ask for,
+// either to get an item or, with query set, to query a partition
+func (m TableDataModel) loadKeySchema(tableName string, query bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			log.Printf("Failed to describe table: %v", err)
+			return KeySchemaLoadedMsg{region: m.region, table: tableName, err: err}
+		}
+
+		partitionKey, sortKey, err := extractPrimaryKeyAttributes(tableInfo.KeySchema)
+		if err != nil {
+			return KeySchemaLoadedMsg{region: m.region, table: tableName, err: err}
+		}
+
+		attrTypes := make(map[string]types.ScalarAttributeType)
+		for _, definition := range tableInfo.AttributeDefinitions {
+			attrTypes[aws.ToString(definition.AttributeName)] = definition.AttributeType
+		}
+
+		keys := []keyAttribute{{name: partitionKey, attrType: attrTypes[partitionKey]}}
+		if sortKey != nil {
+			keys = append(keys, keyAttribute{name: *sortKey, attrType: attrTypes[*sortKey]})
+		}
+		return KeySchemaLoadedMsg{region: m.region, table: tableName, keys: keys, query: query}
+	}
+}
+
+// promptNextKey asks for the next key attribute of the pending lookup
+func (m *MainModel) promptNextKey() tea.Cmd {
+	next := m.pendingGet.keys[len(m.pendingGet.values)]
+	return m.openPrompt(getItemPrompt, fmt.Sprintf("Get item — %s (%s):", next.name, next.attrType))
+}
+
+// submitKeyValue records an entered key value and either asks for the next one
+// or looks the item up. An empty value cancels the lookup.
+func (m *MainModel) submitKeyValue(value string) tea.Cmd {
+	pending := m.pendingGet
+	if pending == nil || value == "" {
+		m.pendingGet = nil
+		return nil
+	}
+
+	pending.values = append(pending.values, value)
+	if len(pending.values) < len(pending.keys) {
+		return m.promptNextKey()
+	}
+	m.pendingGet = nil
+
+	key, err := itemKey(pending.keys, pending.values)
+	if err != nil {
+		m.lastErr = err
+		return nil
+	}
+
+	described := make([]string, len(pending.keys))
+	for i, attribute := range pending.keys {
+		described[i] = attribute.name + "=" + pending.values[i]
+	}
+
+	m.tableDataModel.lookingUp = true
+	return tea.Batch(m.tableDataModel.getItem(pending.table, key, strings.Join(described, ", ")), m.tableDataModel.loadingIndicator.Tick)
+}
+
+// itemKey builds a DynamoDB key from values entered for the key attributes,
+// converting each to its declared type
+func itemKey(keys []keyAttribute, values []string) (map[string]types.AttributeValue, error) {
+	key := make(map[string]types.AttributeValue, len(keys))
+	for i, attribute := range keys {
+		switch attribute.attrType {
+		case types.ScalarAttributeTypeN:
+			if _, err := strconv.ParseFloat(values[i], 64); err != nil {
+				return nil, fmt.Errorf("%s is a number, got %q", attribute.name, values[i])
+			}
+			key[attribute.name] = &types.AttributeValueMemberN{Value: values[i]}
+		case types.ScalarAttributeTypeB:
+			data, err := tools.ParseBinaryValue(values[i])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", attribute.name, err)
+			}
+			key[attribute.name] = &types.AttributeValueMemberB{Value: data}
+		default:
+			key[attribute.name] = &types.AttributeValueMemberS{Value: values[i]}
+		}
+	}
+	return key, nil
+}
+
+// getItem reads a single item by its key
+func (m TableDataModel) getItem(tableName string, key map[string]types.AttributeValue, described string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		msg := ItemFetchedMsg{region: m.region, table: tableName, key: described}
+		output, err := m.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: &tableName,
+			Key:       key,
+		})
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		if output.Item == nil {
+			return msg
+		}
+
+		keys := make(map[string]bool, len(key))
+		for name := range key {
+			keys[name] = true
+		}
+		item, err := tools.DynamoItemToRowMap(output.Item, keys)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+
+		msg.row = string(data)
+		msg.found = true
+		return msg
+	}
+}
@@ -0,0 +1,91 @@
+package lazydynamo
+
+import (
+	"github.com/TheChessDev/lazydynamo/internals/components"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// helpSections gathers the key bindings of every mode for the help overlay
+func (m MainModel) helpSections() []components.HelpSection {
+	collectionKeys := m.collectionsList.KeyMap
+
+	return []components.HelpSection{
+		{
+			Title:    "Global",
+			Bindings: []key.Binding{m.keys.Collections, m.keys.Data, m.keys.SwitchPane, m.keys.ViewMode, m.keys.Logs, m.keys.ErrorDetails, m.keys.Reconnect, m.keys.ShrinkPane, m.keys.GrowPane, m.keys.RegionPane, m.keys.Help, m.keys.Quit},
+		},
+		{
+			Title: "Collections",
+			Bindings: []key.Binding{
+				m.keys.Up, m.keys.Down, collectionKeys.GoToStart, collectionKeys.GoToEnd, collectionKeys.Filter,
+				m.keys.FilterMode, m.keys.SelectCollection, m.keys.Refresh, m.keys.CopyName, m.keys.CopyArn, m.keys.DeleteTable, m.keys.Caches, m.keys.Pin,
+			},
+		},
+		{
+			Title:    "Region",
+			Bindings: []key.Binding{m.keys.SwitchRegion},
+		},
+		{
+			Title:    "Data",
+			Bindings: append(flattenBindings(m.tableDataModel.keys.FullHelp()), m.tableDataModel.dataList.KeyMap.Filter),
+		},
+		{
+			Title:    "Row",
+			Bindings: flattenBindings(m.viewRowModel.keys.FullHelp()),
+		},
+		{
+			Title:    "Edit Item",
+			Bindings: flattenBindings(m.editItemModel.keys.FullHelp()),
+		},
+		{
+			Title:    "Caches",
+			Bindings: append(flattenBindings(m.viewCachesModel.keys.FullHelp()), m.viewCachesModel.list.KeyMap.Filter),
+		},
+		{
+			Title:    "Schema",
+			Bindings: flattenBindings(m.viewSchemaModel.keys.FullHelp()),
+		},
+		{
+			Title:    "Diff",
+			Bindings: flattenBindings(m.viewDiffModel.keys.FullHelp()),
+		},
+		{
+			Title:    "Error details",
+			Bindings: flattenBindings(m.viewErrorModel.keys.FullHelp()),
+		},
+		{
+			Title:    "Stream",
+			Bindings: flattenBindings(m.viewStreamModel.keys.FullHelp()),
+		},
+		{
+			Title:    "Logs",
+			Bindings: flattenBindings(m.viewLogsModel.keys.FullHelp()),
+		},
+	}
+}
+
+// acceptsGlobalKey reports whether a global key such as ? should act rather
+// than be typed into a filter or the editor
+func (m MainModel) acceptsGlobalKey() bool {
+	switch m.state {
+	case EditingItem:
+		return false
+	case ViewingCollections:
+		return m.collectionsList.FilterState() != list.Filtering
+	case ViewingData:
+		return m.tableDataModel.dataList.FilterState() != list.Filtering
+	case ViewingCaches:
+		return m.viewCachesModel.list.FilterState() != list.Filtering
+	}
+	return true
+}
+
+// flattenBindings joins the columns of a FullHelp into one list
+func flattenBindings(columns [][]key.Binding) []key.Binding {
+	var bindings []key.Binding
+	for _, column := range columns {
+		bindings = append(bindings, column...)
+	}
+	return bindings
+}
@@ -0,0 +1,143 @@
+package lazydynamo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// importJob tracks an import that is written to the table chunk by chunk
+type importJob struct {
+	region   string
+	client   *dynamodb.Client // client for region, which the user may have left
+	table    string
+	requests []types.WriteRequest
+	rows     []string
+	next     int
+	written  int
+	failures []error
+}
+
+// ImportLoadedMsg is sent once the import file was read and validated
+type ImportLoadedMsg struct {
+	region string
+	table  string
+	job    *importJob
+	err    error
+}
+
+// ImportProgressMsg is sent after each chunk of an import was written
+type ImportProgressMsg struct {
+	job  *importJob
+	rows []string
+	err  error
+}
+
+// loadImportFile reads the items to import and converts the valid ones into
+// put requests. Items failing validation are recorded but don't stop the import.
+func (m TableDataModel) loadImportFile(tableName string, path string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			return ImportLoadedMsg{region: m.region, table: tableName, err: err}
+		}
+
+		objects, failures, err := tools.ReadItemsFile(path)
+		if err != nil {
+			return ImportLoadedMsg{region: m.region, table: tableName, err: err}
+		}
+
+		job := &importJob{region: m.region, client: m.client, table: tableName, failures: failures}
+		for i, obj := range objects {
+			if _, err := tools.ExtractItemKey(obj, tableInfo); err != nil {
+				job.failures = append(job.failures, fmt.Errorf("item %d: %w", i+1, err))
+				continue
+			}
+
+			item, err := tools.MapToDynamoItem(obj)
+			if err != nil {
+				job.failures = append(job.failures, fmt.Errorf("item %d: %w", i+1, err))
+				continue
+			}
+
+			row, err := itemToRow(item, tableInfo)
+			if err != nil {
+				job.failures = append(job.failures, fmt.Errorf("item %d: %w", i+1, err))
+				continue
+			}
+
+			job.requests = append(job.requests, types.WriteRequest{
+				PutRequest: &types.PutRequest{Item: item},
+			})
+			job.rows = append(job.rows, row)
+		}
+
+		return ImportLoadedMsg{region: m.region, table: tableName, job: job}
+	}
+}
+
+// importNextChunk writes the next chunk of the import, to the region the
+// import started in
+func importNextChunk(job *importJob) tea.Cmd {
+	start := job.next
+	end := min(start+batchWriteSize, len(job.requests))
+	job.next = end
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := batchWrite(ctx, job.client, job.table, job.requests[start:end]); err != nil {
+			log.Printf("Failed to import items %d-%d: %v", start+1, end, err)
+			return ImportProgressMsg{job: job, err: fmt.Errorf("items %d-%d: %w", start+1, end, err)}
+		}
+
+		return ImportProgressMsg{job: job, rows: job.rows[start:end]}
+	}
+}
+
+// done reports whether every chunk of the import was sent
+func (job *importJob) done() bool {
+	return job.next >= len(job.requests)
+}
+
+// summary describes the import progress for the status bar
+func (job *importJob) summary() string {
+	return fmt.Sprintf("imported %d/%d items", job.written, len(job.requests))
+}
+
+// failureError summarizes the items that couldn't be imported, if any
+func (job *importJob) failureError() error {
+	if len(job.failures) == 0 {
+		return nil
+	}
+	for _, err := range job.failures {
+		log.Println("Import failure:", err)
+	}
+	return fmt.Errorf("%d items failed to import (see logs), first: %w", len(job.failures), job.failures[0])
+}
+
+// itemToRow converts an item into the single-line JSON shown in the data
+// list, keeping binary keys whole so the row can be written to
+func itemToRow(item map[string]types.AttributeValue, tableInfo *types.TableDescription) (string, error) {
+	mapItem, err := tools.DynamoItemToRowMap(item, tools.KeyAttributes(tableInfo))
+	if err != nil {
+		return "", err
+	}
+	row, err := json.Marshal(mapItem)
+	if err != nil {
+		return "", err
+	}
+	return string(row), nil
+}
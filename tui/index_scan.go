@@ -0,0 +1,107 @@
+package lazydynamo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// IndexesLoadedMsg carries the secondary indexes of a table, with the
+// attributes each one projects
+type IndexesLoadedMsg struct {
+	region      string
+	table       string
+	projections map[string]string
+	err         error
+}
+
+// loadIndexes describes the table to list its secondary indexes
+func (m TableDataModel) loadIndexes(tableName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			log.Printf("Failed to describe table: %v", err)
+			return IndexesLoadedMsg{region: m.region, table: tableName, err: err}
+		}
+
+		projections := make(map[string]string)
+		for _, index := range tableInfo.GlobalSecondaryIndexes {
+			projections[aws.ToString(index.IndexName)] = describeProjection(index.Projection)
+		}
+		for _, index := range tableInfo.LocalSecondaryIndexes {
+			projections[aws.ToString(index.IndexName)] = describeProjection(index.Projection)
+		}
+
+		return IndexesLoadedMsg{region: m.region, table: tableName, projections: projections}
+	}
+}
+
+// allAttributesProjection describes an index that projects the whole item
+const allAttributesProjection = "all attributes"
+
+// describeProjection summarizes which attributes an index holds
+func describeProjection(projection *types.Projection) string {
+	if projection == nil {
+		return "keys only"
+	}
+
+	switch projection.ProjectionType {
+	case types.ProjectionTypeAll:
+		return allAttributesProjection
+	case types.ProjectionTypeInclude:
+		return "keys and " + strings.Join(projection.NonKeyAttributes, ", ")
+	default:
+		return "keys only"
+	}
+}
+
+// setScanIndex makes the following scans read the given index instead of the
+// base table, and rescans. An empty index goes back to the base table.
+func (m *TableDataModel) setScanIndex(index string) (tea.Cmd, error) {
+	projection, ok := m.indexProjections[index]
+	if index != "" && !ok {
+		return nil, fmt.Errorf("table %s has no index %q", m.selectedTable, index)
+	}
+
+	m.scanIndex = index
+	m.scanIndexProjection = projection
+	m.loading = true
+	m.dataLoaded = false
+
+	return tea.Batch(m.dataList.SetItems(nil), m.startFetch(m.selectedTable, false), m.loadingIndicator.Tick), nil
+}
+
+// partialScan reports whether scans return less than the whole base table,
+// in which case their results must not replace the table's cache
+func (m TableDataModel) partialScan() bool {
+	return m.scanFilter != nil || m.scanIndex != "" || m.sampling || m.query != nil
+}
+
+// projectedRows reports whether the rows come from an index that doesn't hold
+// every attribute, so writing them back would drop the rest of the item
+func (m TableDataModel) projectedRows() bool {
+	return m.scanIndex != "" && m.scanIndexProjection != allAttributesProjection
+}
+
+// allowRowWrite is allowWrite for writes based on the loaded rows. Rows of an
+// index that only projects some attributes aren't whole items, so they can't
+// be written back or used to pick items to delete.
+func (m *MainModel) allowRowWrite() bool {
+	if !m.allowWrite() {
+		return false
+	}
+	if m.tableDataModel.projectedRows() {
+		m.lastErr = fmt.Errorf("rows from index %s hold only %s — press i and leave the index empty to write to the table", m.tableDataModel.scanIndex, m.tableDataModel.scanIndexProjection)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package lazydynamo
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultLiveInterval = 5 * time.Second
+	liveIntervalStep    = time.Second
+	minLiveInterval     = time.Second
+)
+
+// LiveTickMsg triggers the next scan of a table in live refresh mode. The
+// generation drops ticks scheduled before live refresh was toggled.
+type LiveTickMsg struct {
+	region     string
+	table      string
+	generation int
+}
+
+// toggleLive turns live refresh of the selected table on or off
+func (m *TableDataModel) toggleLive() tea.Cmd {
+	m.liveGeneration++
+	if m.liveInterval != 0 {
+		m.liveInterval = 0
+		return nil
+	}
+
+	m.liveInterval = defaultLiveInterval
+	return m.tickLive()
+}
+
+// adjustLive changes the live refresh interval by delta, restarting the timer
+func (m *TableDataModel) adjustLive(delta time.Duration) tea.Cmd {
+	if m.liveInterval == 0 {
+		return nil
+	}
+
+	m.liveInterval = max(m.liveInterval+delta, minLiveInterval)
+	m.liveGeneration++
+	return m.tickLive()
+}
+
+// stopLive turns live refresh off, e.g. when another table is selected
+func (m *TableDataModel) stopLive() {
+	m.liveInterval = 0
+	m.liveGeneration++
+}
+
+func (m TableDataModel) tickLive() tea.Cmd {
+	msg := LiveTickMsg{region: m.region, table: m.selectedTable, generation: m.liveGeneration}
+	return tea.Tick(m.liveInterval, func(time.Time) tea.Msg {
+		return msg
+	})
+}
+
+// handleLiveTick rescans the table unless the previous scan is still running,
+// so slow scans never stack up, and schedules the next tick
+func (m *TableDataModel) handleLiveTick(msg LiveTickMsg) tea.Cmd {
+	if msg.generation != m.liveGeneration || !m.isSelected(msg.region, msg.table) || m.liveInterval == 0 {
+		return nil
+	}
+
+	if m.loading {
+		return m.tickLive()
+	}
+
+	m.loading = true
+	return tea.Batch(m.startBackgroundRefresh(m.selectedTable), m.loadingIndicator.Tick, m.tickLive())
+}
@@ -1,11 +1,17 @@
 package lazydynamo
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"fmt"
 	"log"
@@ -15,28 +21,43 @@ import (
 	"golang.org/x/term"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type sessionState int
 
-type TablesFetchedMsg []list.Item
+var errReadOnly = errors.New("read-only mode: write actions are disabled")
+
+// TablesFetchedMsg carries the collections of a region. The region is used to
+// drop results that arrive after the user switched to another region.
+type TablesFetchedMsg struct {
+	region    string
+	items     []list.Item
+	fromCache bool // items were loaded from the cache and a refresh should follow
+}
 
 const (
 	ViewingCollections sessionState = iota
 	ViewingData
 	ViewMode
 	ViewingRow
+	EditingItem
+	ViewingLogs
+	ViewingSchema
+	ViewingDiff
+	ViewingCaches
+	ViewingError
+	ViewingStream
 )
 
 // keyMap defines a set of keybindings. To work for help it must satisfy
@@ -44,6 +65,7 @@ const (
 type keyMap struct {
 	Collections      key.Binding
 	Data             key.Binding
+	SwitchPane       key.Binding
 	Down             key.Binding
 	Help             key.Binding
 	Left             key.Binding
@@ -52,6 +74,26 @@ type keyMap struct {
 	Up               key.Binding
 	ViewMode         key.Binding
 	SelectCollection key.Binding
+	SwitchRegion     key.Binding
+	Refresh          key.Binding
+	Logs             key.Binding
+	ErrorDetails     key.Binding
+	RegionPane       key.Binding
+	CopyName         key.Binding
+	CopyArn          key.Binding
+	DeleteTable      key.Binding
+	Caches           key.Binding
+	Pin              key.Binding
+	FilterMode       key.Binding
+	Reconnect        key.Binding
+	ShrinkPane       key.Binding
+	GrowPane         key.Binding
+}
+
+// disableWrites marks the bindings of write actions as disabled in the help
+func (k *keyMap) disableWrites() {
+	help := k.DeleteTable.Help()
+	k.DeleteTable.SetHelp(help.Key, help.Desc+" (read-only)")
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
@@ -64,8 +106,10 @@ func (k keyMap) ShortHelp() []key.Binding {
 // key.Map interface.
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Collections, k.Data}, // first column
-		{k.Help, k.Quit},        // second column
+		{k.Collections, k.Data, k.SwitchPane},    // first column
+		{k.Logs, k.ErrorDetails, k.Reconnect},    // second column
+		{k.ShrinkPane, k.GrowPane, k.RegionPane}, // third column
+		{k.Help, k.Quit},                         // fourth column
 	}
 }
 
@@ -78,10 +122,70 @@ var keys = keyMap{
 		key.WithKeys("c"),
 		key.WithHelp("c", "Go to Collections"),
 	),
+	SwitchPane: key.NewBinding(
+		key.WithKeys("tab", "shift+tab"),
+		key.WithHelp("tab", "switch pane"),
+	),
 	SelectCollection: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "Select Collection"),
 	),
+	SwitchRegion: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "switch region (a,b lists several)"),
+	),
+	Refresh: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "refresh"),
+	),
+	Logs: key.NewBinding(
+		key.WithKeys("ctrl+l"),
+		key.WithHelp("ctrl+l", "view logs"),
+	),
+	RegionPane: key.NewBinding(
+		key.WithKeys("ctrl+g"),
+		key.WithHelp("ctrl+g", "hide/show region pane"),
+	),
+	ErrorDetails: key.NewBinding(
+		key.WithKeys("ctrl+e"),
+		key.WithHelp("ctrl+e", "last error details"),
+	),
+	CopyName: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy table name"),
+	),
+	CopyArn: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy table ARN"),
+	),
+	Reconnect: key.NewBinding(
+		key.WithKeys("ctrl+k"),
+		key.WithHelp("ctrl+k", "reconnect to AWS"),
+	),
+	ShrinkPane: key.NewBinding(
+		key.WithKeys("<"),
+		key.WithHelp("<", "shrink collections pane"),
+	),
+	GrowPane: key.NewBinding(
+		key.WithKeys(">"),
+		key.WithHelp(">", "grow collections pane"),
+	),
+	Caches: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "manage caches"),
+	),
+	Pin: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "pin/unpin table"),
+	),
+	FilterMode: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "cycle filter mode"),
+	),
+	DeleteTable: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "delete table"),
+	),
 	Up: key.NewBinding(
 		key.WithKeys("up", "k"),
 		key.WithHelp("↑/k", "move up"),
@@ -100,7 +204,7 @@ var keys = keyMap{
 	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
-		key.WithHelp("?", "toggle help"),
+		key.WithHelp("?", "all keys"),
 	),
 	ViewMode: key.NewBinding(
 		key.WithKeys(tea.KeyEsc.String()),
@@ -113,24 +217,72 @@ var keys = keyMap{
 }
 
 type MainModel struct {
-	state          sessionState
-	tableDataModel TableDataModel
-	viewRowModel   ViewRowModel
+	state           sessionState
+	tableDataModel  TableDataModel
+	viewRowModel    ViewRowModel
+	editItemModel   EditItemModel
+	viewLogsModel   ViewLogsModel
+	viewSchemaModel ViewSchemaModel
+	viewDiffModel   ViewDiffModel
+	viewErrorModel  ViewErrorModel
+	viewCachesModel ViewCachesModel
+	viewStreamModel ViewStreamModel
 
 	keys keyMap
 	help help.Model
 
+	termWidth, termHeight int     // size of the last WindowSizeMsg
+	hideRegionPane        bool    // the AWS region pane is collapsed
+	paneRatio             float64 // share of the width the collections pane takes
+
+	appConfig        tools.Config
+	awsConfig        aws.Config
 	client           *dynamodb.Client
 	dataScrollOffset int
 	ddBuffer         string
 	loading          bool
 	region           string
+	regions          []string // every region listed in multi-region mode
+	clients          map[string]*dynamodb.Client
+	profile          string
+	endpoint         string // DynamoDB endpoint used instead of AWS, if any
+	roleArn          string
+	readOnly         bool
+	accountID        string // looked up on demand to build table ARNs
+	notice           string // short feedback shown in the status bar
+	lastErr          error
+	lastFetchErr     error // last failed fetch, kept after the banner is cleared
 	tables           []tableNameItem
 	collectionsList  list.Model
+	collectionsReady bool // at least one collections fetch has completed
+	refreshingCached bool // cached collections are shown while fresh ones are fetched
+	filterMode       collectionsFilterMode
+	pinned           map[string]bool // pinned tables, keyed by pinKey
 
 	loadingIndicator spinner.Model
 
-	viewport viewport.Model
+	viewport       viewport.Model
+	logsViewport   viewport.Model
+	schemaViewport viewport.Model
+	diffViewport   viewport.Model
+	errorViewport  viewport.Model
+	streamViewport viewport.Model
+
+	prompt      textinput.Model
+	promptKind  promptKind
+	promptWords []string // attribute names the prompt completes, if any
+	history     promptHistory
+
+	confirmDialog components.ConfirmDialog
+	helpOverlay   components.HelpOverlay
+	pendingDelete []string // rows waiting for the batch delete confirmation
+
+	pendingTableDelete *pendingTable
+	pendingEdit        *pendingEdit
+	pendingUpdate      *pendingUpdate
+	pendingGet         *pendingGetItem
+	pendingHeavyScan   *HeavyScanMsg
+	startTable         string // table to open once the collections are listed, if any
 }
 
 var (
@@ -139,13 +291,17 @@ var (
 	selectedItemStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("10"))
 	paginationStyle   = list.DefaultStyles().PaginationStyle.PaddingLeft(4)
 	spinnerStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	matchStyle        = lipgloss.NewStyle().Bold(true).Underline(true)
 )
 
 type tableNameItem string
 
 func (i tableNameItem) FilterValue() string { return string(i) }
 
-type itemDelegate struct{}
+type itemDelegate struct {
+	tags   []tools.TableTag
+	pinned map[tableNameItem]bool
+}
 
 func (d itemDelegate) Height() int                             { return 1 }
 func (d itemDelegate) Spacing() int                            { return 0 }
@@ -161,64 +317,247 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	modelWidth := m.Width()
 	maxWidth := modelWidth - 3
 
-	if len(str) > maxWidth {
-		str = str[:maxWidth-3] + "..." // Truncate and add ellipsis
+	// Tagged tables get their color and a label such as [PROD]
+	var suffix string
+	tag, tagged := d.tagFor(i)
+	if tagged && tag.Label != "" {
+		suffix = " [" + tag.Label + "]"
+		maxWidth -= lipgloss.Width(suffix)
+	}
+
+	// Pinned tables are marked; the marker isn't part of the filter matches
+	marker := ""
+	if d.pinned[i] {
+		marker = pinMarker
+		maxWidth -= lipgloss.Width(marker)
 	}
 
-	fn := itemStyle.Render
+	str, visibleLen := truncateToWidth(str, maxWidth)
+
+	style := itemStyle
+	prefix := ""
 	if index == m.Index() {
-		fn = func(s ...string) string {
-			return selectedItemStyle.Render("> " + strings.Join(s, " "))
+		style = selectedItemStyle
+		prefix = "> "
+	}
+	if tagged {
+		style = style.Foreground(lipgloss.Color(tag.Color))
+	}
+
+	fmt.Fprint(w, style.Render(prefix+marker+highlightMatches(m, index, str, 0, visibleLen, style)+suffix))
+}
+
+// tagFor returns the configured tag of a table. In multi-region mode only the
+// table name after the region is matched.
+func (d itemDelegate) tagFor(item tableNameItem) (tools.TableTag, bool) {
+	name := string(item)
+	if _, table, ok := strings.Cut(name, regionSeparator); ok {
+		name = table
+	}
+	return tools.MatchTableTag(d.tags, name)
+}
+
+// awsProfile returns the name of the AWS profile the SDK resolves credentials from
+func awsProfile(appConfig tools.Config) string {
+	if appConfig.Profile != "" {
+		return appConfig.Profile
+	}
+	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
+		return profile
+	}
+	return "default"
+}
+
+// spinners are the loading spinner styles that can be configured by name
+var spinners = map[string]spinner.Spinner{
+	"line":      spinner.Line,
+	"dot":       spinner.Dot,
+	"minidot":   spinner.MiniDot,
+	"jump":      spinner.Jump,
+	"pulse":     spinner.Pulse,
+	"points":    spinner.Points,
+	"globe":     spinner.Globe,
+	"moon":      spinner.Moon,
+	"monkey":    spinner.Monkey,
+	"meter":     spinner.Meter,
+	"hamburger": spinner.Hamburger,
+	"ellipsis":  spinner.Ellipsis,
+}
+
+// newLoadingIndicator builds the spinner shown next to a pane label while
+// that pane is loading. Every pane uses the configured style and color;
+// unknown or empty ones fall back to the default green line.
+func newLoadingIndicator(appConfig tools.Config) spinner.Model {
+	s := spinner.New()
+	s.Style = spinnerStyle
+	s.Spinner = spinner.Line
+
+	if appConfig.Spinner != "" {
+		if style, ok := spinners[strings.ToLower(appConfig.Spinner)]; ok {
+			s.Spinner = style
+		} else {
+			log.Printf("Ignoring unknown spinner %q", appConfig.Spinner)
 		}
 	}
+	if appConfig.SpinnerColor != "" {
+		s.Style = s.Style.Foreground(lipgloss.Color(appConfig.SpinnerColor))
+	}
 
-	fmt.Fprint(w, fn(str))
+	return s
 }
 
-func New() MainModel {
-	// Load AWS config with custom retry settings
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion("us-east-1"),
-		config.WithRetryer(func() aws.Retryer {
-			return retry.AddWithMaxAttempts(retry.NewStandard(), 20)
-		}),
-	)
+// truncateToWidth shortens str so it fits within maxWidth terminal cells,
+// adding an ellipsis when it had to be cut. Truncation is rune-aware so
+// multibyte content is never split mid-character. It also returns the number
+// of runes kept before the ellipsis.
+func truncateToWidth(str string, maxWidth int) (string, int) {
+	if lipgloss.Width(str) <= maxWidth {
+		return str, utf8.RuneCountInString(str)
+	}
+
+	truncated := ansi.Truncate(str, max(0, maxWidth-3), "")
+
+	return truncated + "...", utf8.RuneCountInString(truncated)
+}
+
+// highlightMatches styles the characters of str that matched the active list
+// filter. str is the part of the item starting at rune offset start; only
+// matches within its first visibleLen characters are styled so that the
+// truncation ellipsis is never highlighted.
+func highlightMatches(m list.Model, index int, str string, start int, visibleLen int, style lipgloss.Style) string {
+	if m.FilterState() == list.Unfiltered {
+		return str
+	}
+
+	var matches []int
+	for _, i := range m.MatchesForItem(index) {
+		if i >= start && i < start+visibleLen {
+			matches = append(matches, i-start)
+		}
+	}
+	if len(matches) == 0 {
+		return str
+	}
+
+	unmatched := lipgloss.NewStyle().Foreground(style.GetForeground())
+	matched := matchStyle.Inherit(unmatched)
 
+	return lipgloss.StyleRunes(str, matches, matched, unmatched)
+}
+
+func New(appConfig tools.Config) MainModel {
+	cfg, err := loadAWSConfig(appConfig)
 	if err != nil {
 		log.Fatalf("unable to load SDK config, %v", err)
 	}
 
-	client := dynamodb.NewFromConfig(cfg)
+	// The styles are shared, so the theme goes first
+	loadTheme(appConfig)
+
+	client := newDynamoClient(cfg, cfg.Region)
+
+	mainKeys := keys
+	if appConfig.ReadOnly {
+		mainKeys.disableWrites()
+	}
 
 	items := []list.Item{}
 
-	l := list.New(items, itemDelegate{}, 10, 10)
+	l := list.New(items, itemDelegate{tags: appConfig.TableTags}, 10, 10)
 
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
 	l.Styles.PaginationStyle = paginationStyle
 	l.SetShowHelp(true)
+	themeHelp(&l.Help.Styles)
 	l.SetShowFilter(true)
 	l.KeyMap.Quit.SetKeys("q", "ctrl-c")
 	l.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{keys.SelectCollection}
+		return []key.Binding{mainKeys.SelectCollection, mainKeys.SwitchRegion, mainKeys.Refresh, mainKeys.CopyName, mainKeys.CopyArn, mainKeys.FilterMode, mainKeys.DeleteTable, mainKeys.Caches, mainKeys.Pin}
 	}
 
-	s := spinner.New()
-	s.Style = spinnerStyle
-	s.Spinner = spinner.Line
+	s := newLoadingIndicator(appConfig)
 
-	return MainModel{
+	tableDataModel := TableDataModel{}.New(client)
+	tableDataModel.loadingIndicator = newLoadingIndicator(appConfig)
+	tableDataModel.scanTimeout = defaultScanTimeout
+	if appConfig.ScanTimeout != "" {
+		if timeout, err := tools.ParseTimeout(appConfig.ScanTimeout); err != nil {
+			log.Printf("Ignoring invalid scan timeout %q: %v", appConfig.ScanTimeout, err)
+		} else {
+			tableDataModel.scanTimeout = timeout
+		}
+	}
+	tableDataModel.scanPageLimit = appConfig.ScanPageLimit
+	tableDataModel.scanSegments = appConfig.ScanSegments
+	tableDataModel.sampleSize = DefaultSampleSize
+	if appConfig.SampleSize > 0 {
+		tableDataModel.sampleSize = appConfig.SampleSize
+	}
+	compressDataCaches = !appConfig.UncompressedCache
+	tableDataModel.flattenDepth = DefaultFlattenDepth
+	if appConfig.FlattenDepth > 0 {
+		tableDataModel.flattenDepth = appConfig.FlattenDepth
+	}
+	viewRowModel := ViewRowModel{}.New()
+	if appConfig.ReadOnly {
+		tableDataModel.keys.disableWrites()
+		viewRowModel.keys.disableWrites()
+	}
+
+	m := MainModel{
 		state:            ViewingCollections,
-		region:           "us-east-1",
+		region:           cfg.Region,
+		profile:          awsProfile(appConfig),
+		endpoint:         dynamoEndpoint(),
+		roleArn:          appConfig.RoleArn,
+		appConfig:        appConfig,
+		readOnly:         appConfig.ReadOnly,
+		paneRatio:        appConfig.PaneRatio,
+		hideRegionPane:   appConfig.HideRegionPane,
+		awsConfig:        cfg,
 		client:           client,
 		loading:          false,
 		help:             help.New(),
-		keys:             keys,
-		tableDataModel:   TableDataModel{}.New(client),
-		viewRowModel:     ViewRowModel{}.New(),
+		keys:             mainKeys,
+		tableDataModel:   tableDataModel,
+		viewRowModel:     viewRowModel,
+		editItemModel:    EditItemModel{}.New(),
+		viewLogsModel:    ViewLogsModel{}.New(),
+		viewSchemaModel:  ViewSchemaModel{}.New(),
+		viewDiffModel:    ViewDiffModel{}.New(),
+		viewErrorModel:   ViewErrorModel{}.New(),
+		viewCachesModel:  ViewCachesModel{}.New(),
+		viewStreamModel:  ViewStreamModel{}.New(),
 		collectionsList:  l,
 		loadingIndicator: s,
+		prompt:           newPrompt(),
+		history:          loadPromptHistory(!appConfig.SessionHistory),
+		confirmDialog:    components.NewConfirmDialog(BoxActiveColor),
+		helpOverlay:      components.NewHelpOverlay(BoxActiveColor),
+	}
+
+	themeHelp(&m.help.Styles)
+
+	regions := appConfig.Regions
+	if len(regions) == 0 {
+		regions = []string{m.region}
+	}
+	m.setRegions(regions)
+
+	pinned, err := tools.LoadPinnedTables(PinnedTablesFilePath)
+	if err != nil {
+		log.Printf("Ignoring unreadable pinned tables: %v", err)
+		pinned = map[string]bool{}
 	}
+	m.pinned = pinned
+
+	m.startTable = appConfig.Table
+	if appConfig.Mode == StartModeView {
+		m.state = ViewMode
+	}
+
+	return m
 }
 
 func (m MainModel) Init() tea.Cmd {
@@ -233,6 +572,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		m.termWidth, m.termHeight = msg.Width, msg.Height
+
 		// If we set a width on the help menu it can gracefully truncate
 		// its view as needed.
 		m.help.Width = msg.Width
@@ -263,32 +604,428 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		collectionListHeight := int(adjustedHeightRatio * float64(msg.Height))
 		dataListHeight := int(dataListHeightRation * float64(msg.Height))
 
+		if m.hideRegionPane {
+			collectionListHeight += regionPaneHeight
+		}
 		m.collectionsList.SetHeight(collectionListHeight)
 		m.tableDataModel.dataList.SetHeight(dataListHeight)
-
-		leftWidth := int(0.3 * float64(msg.Width))
-		m.viewport = viewport.New(msg.Width-leftWidth-6, msg.Height-10)
+		m.tableDataModel.grid.SetHeight(dataListHeight)
+
+		leftWidth := m.leftPaneWidth(msg.Width)
+		scrolled := m.viewport.ScrollPercent()
+		viewportWidth, viewportHeight := max(msg.Width-leftWidth-6, 0), max(msg.Height-10, 0)
+		m.viewport = viewport.New(viewportWidth, viewportHeight)
+		m.logsViewport = viewport.New(viewportWidth, viewportHeight)
+		// Resized in place, the schema isn't recomputed for a new size
+		m.schemaViewport.Width, m.schemaViewport.Height = viewportWidth, viewportHeight
+		m.diffViewport.Width, m.diffViewport.Height = viewportWidth, viewportHeight
+		m.errorViewport.Width, m.errorViewport.Height = viewportWidth, viewportHeight
+		m.streamViewport.Width, m.streamViewport.Height = viewportWidth, viewportHeight
+		if m.tableDataModel.selectedRow != "" {
+			// The row was wrapped for the old width; wrap it again and keep
+			// roughly the same part of it in view. The tree keeps its folds.
+			if m.viewRowModel.treeView && m.viewRowModel.tree != nil {
+				m.renderTree()
+			} else {
+				m.renderRow(m.displayedRow())
+				m.viewport.SetYOffset(int(scrolled * float64(max(m.viewport.TotalLineCount()-m.viewport.Height, 0))))
+			}
+		}
+		m.helpOverlay = m.helpOverlay.SetSize(msg.Width, msg.Height)
+		if m.state == ViewingLogs {
+			m.refreshLogs()
+		}
+		if m.state == ViewingStream {
+			m.refreshStream()
+		}
 
 	case TablesFetchedMsg:
-		cmd := m.collectionsList.SetItems(msg)
+		if msg.region != m.regionKey() {
+			log.Printf("Ignoring stale collections for region %s", msg.region)
+			break
+		}
+		cmd := m.setCollections(msg.items)
 		m.loading = false
-		cmds = append(cmds, cmd)
+		m.lastErr = nil
+		m.collectionsReady = true
+		m.refreshingCached = msg.fromCache
+		cmds = append(cmds, cmd, m.openStartTable(msg.fromCache))
+		if msg.fromCache {
+			cmds = append(cmds, m.refreshCollections())
+		}
 	case TablesFetchStartedMsg:
 		m.loading = true
 		cmds = append(cmds, m.fetchCollections(), m.loadingIndicator.Tick)
 	case DataFetchedMsg:
+		if !m.tableDataModel.isSelected(msg.region, msg.table) {
+			log.Printf("Ignoring stale data for table %s in %s", msg.table, msg.region)
+			break
+		}
+		m.tableDataModel.loading = false
+		m.tableDataModel.updateStreamKey()
+		if msg.background && msg.partial != "" {
+			// Keep the complete rows on screen rather than part of them
+			m.notice = fmt.Sprintf("Refresh incomplete (%s) — kept the rows loaded before", msg.partial)
+			if msg.err != nil {
+				m.lastErr = fetchError(msg.err, m.profile)
+			}
+			break
+		}
+		m.tableDataModel.dataLoaded = true
+		m.tableDataModel.sampled = msg.sampled
+		m.tableDataModel.partial = msg.partial
+		// Scanned rows replace those of a query
+		m.tableDataModel.query = nil
+		m.lastErr = nil
+		if msg.background {
+			cmds = append(cmds, m.tableDataModel.swapRows(msg.items))
+		} else {
+			cmds = append(cmds, m.tableDataModel.setRows(msg.items))
+		}
+		if msg.fromCache {
+			cmds = append(cmds, m.tableDataModel.startBackgroundRefresh(msg.table))
+		} else if msg.partial != "" {
+			m.notice = fmt.Sprintf("⚠ partial results — %s after %s items", msg.partial, formatCount(len(msg.items)))
+			if msg.resumable {
+				m.notice += "; load the table again to resume"
+			}
+			if msg.err != nil {
+				m.lastErr = fetchError(msg.err, m.profile)
+			}
+		} else if msg.sampled && !msg.background {
+			m.notice = fmt.Sprintf("sampled first %s items — %.1f RCU", formatCount(len(msg.items)), msg.consumedCapacity)
+		} else if msg.paced > 0 && !msg.background {
+			m.notice = fmt.Sprintf("scan complete — %.1f RCU, paced to %.0f RCU/s for the provisioned table", msg.consumedCapacity, msg.paced)
+		} else if !msg.background {
+			m.notice = fmt.Sprintf("scan complete — %.1f RCU", msg.consumedCapacity)
+		} else if msg.rescan {
+			m.notice = fmt.Sprintf("rescan complete — %s items, %.1f RCU", formatCount(len(msg.items)), msg.consumedCapacity)
+		}
+		if msg.resumed > 0 && msg.partial == "" {
+			m.notice += fmt.Sprintf(" (resumed after %s cached items)", formatCount(msg.resumed))
+		}
+		if msg.warning != "" {
+			m.notice = msg.warning
+		}
+		if !msg.background && !msg.stopped {
+			m.tableDataModel.setHorizontalOffset(0)
+			m.state = ViewingData
+		}
+	case HeavyScanMsg:
+		m.tableDataModel.loading = false
+		if m.tableDataModel.isSelected(msg.region, msg.table) {
+			m.confirmHeavyScan(msg)
+		}
+	case NewItemTemplateMsg:
+		m.tableDataModel.loading = false
+		m.state = EditingItem
+		cmds = append(cmds, m.editItemModel.open(msg))
+	case ItemSavedMsg:
+		if !m.tableDataModel.isSelected(msg.region, msg.table) {
+			break
+		}
+		m.tableDataModel.loading = false
+		if msg.replaces != "" {
+			cmds = append(cmds, m.tableDataModel.replaceRow(msg.replaces, msg.row))
+			if m.state == ViewingRow {
+				m.openRow(msg.row)
+			}
+			m.notice = "Item saved"
+			if m.tableDataModel.showGrid {
+				m.tableDataModel.refreshGrid()
+			}
+			break
+		}
+		if m.state == EditingItem {
+			m.editItemModel.close()
+			m.state = ViewingData
+		}
+		cmds = append(cmds, m.tableDataModel.dataList.InsertItem(len(m.tableDataModel.dataList.Items()), tableDataRow(msg.row)))
+		if m.tableDataModel.showGrid {
+			m.tableDataModel.refreshGrid()
+		}
+	case ItemSaveFailedMsg:
+		m.tableDataModel.loading = false
+		m.editItemModel.err = msg.error
+		if m.state != EditingItem {
+			m.lastErr = msg.error
+		}
+	case EditableItemMsg:
+		m.tableDataModel.lookingUp = false
+		if msg.err != nil {
+			m.lastErr = friendlyAWSError(msg.err, m.profile)
+			break
+		}
+		if m.state == ViewingRow && msg.row == m.tableDataModel.selectedRow {
+			cmds = append(cmds, openInEditor(msg.row, msg.item))
+		}
+	case EditorClosedMsg:
+		m.handleEditorClosed(msg)
+	case components.ConfirmResultMsg:
+		cmds = append(cmds, m.handleConfirmation(msg))
+	case SortKeyResolvedMsg:
+		if m.tableDataModel.isSelected(msg.region, msg.table) {
+			m.notice = fmt.Sprintf("Newest first by %s", msg.attribute)
+			cmds = append(cmds, m.tableDataModel.sortByAttribute(msg.attribute, true))
+		}
+	case components.ConfirmCopyMsg:
+		m.copyText(msg.Text, "request preview")
+	case WritePreviewMsg:
+		cmds = append(cmds, m.showWritePreview(msg))
+	case RowsDeletedMsg:
+		if !m.tableDataModel.isSelected(msg.region, msg.table) {
+			break
+		}
+		m.tableDataModel.loading = false
+		m.lastErr = msg.err
+		cmds = append(cmds, m.tableDataModel.removeRows(msg.deleted))
+	case ImportLoadedMsg:
+		// The import goes on when another table was selected meanwhile, but
+		// leaves the spinner of that table's scan alone
+		selected := m.tableDataModel.isSelected(msg.region, msg.table)
+		if msg.err != nil {
+			if selected {
+				m.tableDataModel.loading = false
+			}
+			m.lastErr = msg.err
+			break
+		}
+		m.notice = msg.job.summary()
+		if msg.job.done() {
+			if selected {
+				m.tableDataModel.loading = false
+			}
+			m.lastErr = msg.job.failureError()
+			break
+		}
+		cmds = append(cmds, importNextChunk(msg.job))
+	case ImportProgressMsg:
+		job := msg.job
+		if msg.err != nil {
+			job.failures = append(job.failures, msg.err)
+		} else {
+			job.written += len(msg.rows)
+			if m.tableDataModel.isSelected(job.region, job.table) {
+				for _, row := range msg.rows {
+					cmds = append(cmds, m.tableDataModel.dataList.InsertItem(len(m.tableDataModel.dataList.Items()), tableDataRow(row)))
+				}
+			}
+		}
+		m.notice = job.summary()
+		if job.done() {
+			m.lastErr = job.failureError()
+			invalidateTableDataCache(job.region, job.table)
+			if m.tableDataModel.isSelected(job.region, job.table) {
+				m.tableDataModel.loading = false
+				if m.tableDataModel.showGrid {
+					m.tableDataModel.refreshGrid()
+				}
+			}
+			break
+		}
+		cmds = append(cmds, importNextChunk(job))
+	case KeySchemaLoadedMsg:
+		m.tableDataModel.lookingUp = false
+		if !m.tableDataModel.isSelected(msg.region, msg.table) {
+			break
+		}
+		if msg.err != nil {
+			m.lastErr = friendlyAWSError(msg.err, m.profile)
+			break
+		}
+		m.pendingGet = &pendingGetItem{table: msg.table, keys: msg.keys}
+		if msg.query {
+			partition := msg.keys[0]
+			cmds = append(cmds, m.openPrompt(queryPrompt, fmt.Sprintf("Query — %s (%s; empty scans the table):", partition.name, partition.attrType)))
+			break
+		}
+		cmds = append(cmds, m.promptNextKey())
+	case QueryPageMsg:
+		if !m.tableDataModel.isSelected(msg.region, msg.table) || m.tableDataModel.query == nil {
+			break
+		}
+		cmds = append(cmds, m.handleQueryPage(msg))
+	case ItemFetchedMsg:
+		m.tableDataModel.lookingUp = false
+		if !m.tableDataModel.isSelected(msg.region, msg.table) {
+			break
+		}
+		if msg.err != nil {
+			m.lastErr = friendlyAWSError(msg.err, m.profile)
+			break
+		}
+		m.lastErr = nil
+		if !msg.found {
+			m.notice = fmt.Sprintf("Item not found: no item with %s in %s", msg.key, msg.table)
+			break
+		}
+		if m.state == ViewingData {
+			m.openRow(msg.row)
+		}
+	case ReferenceResolvedMsg:
+		if m.state == ViewingRow && m.tableDataModel.region == msg.region {
+			cmds = append(cmds, m.openReference(msg))
+		} else {
+			m.tableDataModel.lookingUp = false
+		}
+	case IndexesLoadedMsg:
+		m.tableDataModel.lookingUp = false
+		if !m.tableDataModel.isSelected(msg.region, msg.table) {
+			break
+		}
+		if msg.err != nil {
+			m.lastErr = friendlyAWSError(msg.err, m.profile)
+			break
+		}
+		m.tableDataModel.indexProjections = msg.projections
+		if len(msg.projections) == 0 {
+			m.notice = fmt.Sprintf("%s has no secondary indexes", msg.table)
+			break
+		}
+		names := make([]string, 0, len(msg.projections))
+		for name := range msg.projections {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		cmds = append(cmds, m.openPrompt(indexPrompt, fmt.Sprintf("Index (%s; empty for the table, tab completes):", strings.Join(names, ", "))))
+		m.prompt.SetSuggestions(names)
+		m.prompt.ShowSuggestions = true
+	case CachesListedMsg:
+		if msg.err != nil {
+			m.lastErr = fmt.Errorf("couldn't list caches: %w", msg.err)
+			break
+		}
+		cmds = append(cmds, m.viewCachesModel.setCaches(msg.caches))
+	case CachesDeletedMsg:
+		m.notice = fmt.Sprintf("Deleted %d cache files", msg.deleted)
+		if msg.err != nil {
+			m.lastErr = fmt.Errorf("couldn't delete a cache: %w", msg.err)
+		}
+		cmds = append(cmds, listCaches())
+	case LiveTickMsg:
+		if m.state != ViewingData {
+			// Live refresh only runs while the rows are on screen
+			m.tableDataModel.stopLive()
+			break
+		}
+		cmds = append(cmds, m.tableDataModel.handleLiveTick(msg))
+	case TypedRowFetchedMsg:
+		m.tableDataModel.lookingUp = false
+		if msg.err != nil {
+			m.lastErr = friendlyAWSError(msg.err, m.profile)
+			if msg.row == m.tableDataModel.selectedRow {
+				m.viewRowModel.showTypes = false
+			}
+			break
+		}
+		m.viewRowModel.typedRows[msg.row] = msg.typed
+		if m.state == ViewingRow && m.viewRowModel.showTypes && m.tableDataModel.selectedRow == msg.row {
+			m.renderRow(msg.typed)
+		}
+	case TableDeletedMsg:
 		m.loading = false
-		m.tableDataModel.dataList.SetItems(msg)
-		m.state = ViewingData
+		if msg.err != nil {
+			m.lastErr = friendlyAWSError(msg.err, m.profile)
+			break
+		}
+		m.notice = fmt.Sprintf("Deleted table %s", msg.table)
+		cmds = append(cmds, m.removeCollection(msg.region, msg.table))
+	case AccountIDFetchedMsg:
+		m.accountID = msg.account
+		m.copyText(tableArn(msg.region, m.accountID, msg.table), "table ARN")
+	case ScanCancelledMsg:
+		// The scan was cancelled on purpose; keep showing what we have
+	case FetchErrorMsg:
+		log.Println("Fetch failed:", msg.error)
+		m.loading = false
+		m.refreshingCached = false
+		m.tableDataModel.loading = false
+		m.lastErr = fetchError(msg.error, m.profile)
+		m.lastFetchErr = m.lastErr
+	case ReconnectedMsg:
+		cmds = append(cmds, m.handleReconnected(msg))
+	case LogTickMsg:
+		if m.state == ViewingLogs && msg.generation == m.viewLogsModel.generation {
+			m.refreshLogs()
+			cmds = append(cmds, m.viewLogsModel.tickLogs())
+		}
+	case StreamShardsMsg:
+		cmds = append(cmds, m.handleStreamShards(msg))
+	case StreamRecordsMsg:
+		cmds = append(cmds, m.handleStreamRecords(msg))
+	case StreamTickMsg:
+		if m.state == ViewingStream && msg.generation == m.viewStreamModel.generation {
+			cmds = append(cmds, m.viewStreamModel.pollStream())
+		}
+	}
+
+	if m.confirmDialog.Active() {
+		if _, ok := msg.(tea.KeyMsg); ok {
+			m.confirmDialog, cmd = m.confirmDialog.Update(msg)
+			return m, cmd
+		}
+	}
+
+	if m.helpOverlay.Active() {
+		if _, ok := msg.(tea.KeyMsg); ok {
+			m.helpOverlay, cmd = m.helpOverlay.Update(msg)
+			return m, cmd
+		}
+	}
+
+	if m.promptKind != noPrompt {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return m, m.updatePrompt(msg)
+		}
+
+		m.prompt, cmd = m.prompt.Update(msg)
 		cmds = append(cmds, cmd)
 	}
 
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Help) && m.acceptsGlobalKey() {
+		m.helpOverlay = m.helpOverlay.Open(m.helpSections())
+		return m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.ShrinkPane, m.keys.GrowPane) && m.acceptsGlobalKey() {
+		if key.Matches(msg, m.keys.ShrinkPane) {
+			return m, m.adjustPaneRatio(-paneRatioStep)
+		}
+		return m, m.adjustPaneRatio(paneRatioStep)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.RegionPane) && m.acceptsGlobalKey() {
+		return m, m.toggleRegionPane()
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.SwitchPane) && m.acceptsGlobalKey() && m.switchPane() {
+		return m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Reconnect) && m.state != EditingItem {
+		m.loading = true
+		m.notice = "Reconnecting..."
+		return m, tea.Batch(reconnect(m.appConfig), m.loadingIndicator.Tick)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.ErrorDetails) && m.state != ViewingError && m.acceptsGlobalKey() {
+		m.openErrorDetails()
+		return m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Logs) && m.state != ViewingLogs && m.state != EditingItem {
+		m.viewLogsModel.previousState = m.state
+		m.viewLogsModel.generation++
+		m.state = ViewingLogs
+		m.refreshLogs()
+		m.logsViewport.GotoBottom()
+		return m, m.viewLogsModel.tickLogs()
+	}
+
 	if !m.EditMode() {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
 			switch {
-			case key.Matches(msg, m.keys.Help):
-				m.help.ShowAll = !m.help.ShowAll
 			case key.Matches(msg, m.keys.Quit):
 				return m, tea.Quit
 			}
@@ -316,16 +1053,75 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyMsg:
 			switch {
 			case key.Matches(msg, m.keys.ViewMode):
+				if m.tableDataModel.loading {
+					// Esc first stops a scan in flight, keeping the rows read so far
+					m.tableDataModel.interruptScan()
+					m.tableDataModel.loading = false
+					return m, nil
+				}
 				m.state = ViewMode
 				return m, nil
+			case key.Matches(msg, m.keys.SwitchRegion):
+				if !(m.collectionsList.FilterState() == list.Filtering) {
+					return m, m.openPrompt(regionPrompt, "Region (comma separated for several):")
+				}
+			case key.Matches(msg, m.keys.Refresh):
+				if !(m.collectionsList.FilterState() == list.Filtering) {
+					m.loading = true
+					return m, tea.Batch(m.refreshCollections(), m.loadingIndicator.Tick)
+				}
+			case key.Matches(msg, m.keys.CopyName):
+				if !(m.collectionsList.FilterState() == list.Filtering) {
+					if i, ok := m.collectionsList.SelectedItem().(tableNameItem); ok {
+						_, table := m.splitCollectionItem(i)
+						m.copyText(table, "table name")
+					}
+					return m, nil
+				}
+			case key.Matches(msg, m.keys.CopyArn):
+				if !(m.collectionsList.FilterState() == list.Filtering) {
+					if i, ok := m.collectionsList.SelectedItem().(tableNameItem); ok {
+						return m, m.copyTableArn(m.splitCollectionItem(i))
+					}
+					return m, nil
+				}
+			case key.Matches(msg, m.keys.FilterMode):
+				if !(m.collectionsList.FilterState() == list.Filtering) {
+					m.filterMode = m.filterMode.next()
+					m.collectionsList.ResetFilter()
+					m.collectionsList.Filter = m.filterMode.filter()
+					m.notice = "Collections filter: " + m.filterMode.String()
+					return m, nil
+				}
+			case key.Matches(msg, m.keys.Caches):
+				if !(m.collectionsList.FilterState() == list.Filtering) {
+					m.viewCachesModel.previous = m.state
+					m.state = ViewingCaches
+					return m, listCaches()
+				}
+			case key.Matches(msg, m.keys.Pin):
+				if !(m.collectionsList.FilterState() == list.Filtering) {
+					return m, m.togglePin()
+				}
+			case key.Matches(msg, m.keys.DeleteTable):
+				if !(m.collectionsList.FilterState() == list.Filtering) {
+					if !m.allowWrite() {
+						return m, nil
+					}
+					if i, ok := m.collectionsList.SelectedItem().(tableNameItem); ok {
+						m.confirmDeleteTable(i)
+					}
+					return m, nil
+				}
 			case key.Matches(msg, m.keys.SelectCollection):
 				if !(m.collectionsList.FilterState() == list.Filtering) {
 					i, ok := m.collectionsList.SelectedItem().(tableNameItem)
 					if ok {
-						m.loading = true
-						m.tableDataModel.selectedTable = string(i)
+						region, table := m.splitCollectionItem(i)
+						m.tableDataModel.loading = true
+						m.tableDataModel.selectTable(m.clientFor(region), region, table)
 					}
-					cmds = append(cmds, m.tableDataModel.fetchAllData(m.tableDataModel.selectedTable), m.loadingIndicator.Tick)
+					cmds = append(cmds, m.tableDataModel.startScan(m.tableDataModel.selectedTable), m.tableDataModel.loadingIndicator.Tick)
 				}
 			}
 		}
@@ -341,76 +1137,611 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyMsg:
 			switch {
 			case key.Matches(msg, m.keys.ViewMode):
+				if m.tableDataModel.loading {
+					// Esc first stops a scan in flight, keeping the rows read so far
+					m.tableDataModel.interruptScan()
+					m.tableDataModel.loading = false
+					return m, nil
+				}
 				m.state = ViewMode
 				return m, nil
 
-			case key.Matches(msg, m.tableDataModel.keys.SelectRow):
+			case key.Matches(msg, m.tableDataModel.keys.Top):
 				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
-					i, ok := m.tableDataModel.dataList.SelectedItem().(tableDataRow)
-					if ok {
-						m.tableDataModel.selectedRow = string(i)
+					m.tableDataModel.gotoRow(0)
+					return m, nil
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.Bottom):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					m.tableDataModel.gotoRow(len(m.tableDataModel.dataList.VisibleItems()) - 1)
+					return m, nil
+				}
 
-						var dataContent string
-						var err error
-						dataContent, err = tools.RenderJSONWithGlamour(m.tableDataModel.selectedRow)
+			case key.Matches(msg, m.tableDataModel.keys.ScrollLeft):
+				if m.tableDataModel.showGrid {
+					m.tableDataModel.scrollGrid(-1)
+					return m, nil
+				}
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					m.tableDataModel.setHorizontalOffset(m.tableDataModel.hOffset - horizontalScrollStep)
+				}
 
-						if err != nil {
-							dataContent = "Could not render row."
-						}
+			case key.Matches(msg, m.tableDataModel.keys.ScrollRight):
+				if m.tableDataModel.showGrid {
+					m.tableDataModel.scrollGrid(1)
+					return m, nil
+				}
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					m.tableDataModel.setHorizontalOffset(m.tableDataModel.hOffset + horizontalScrollStep)
+				}
 
-						m.viewport.SetContent(dataContent)
+			case m.tableDataModel.showGrid && key.Matches(msg, m.tableDataModel.dataList.KeyMap.Filter):
+				// The grid has no filter input, so filtering happens in the
+				// list; toggling the grid back on shows the filtered rows
+				m.tableDataModel.showGrid = false
 
-						m.state = ViewingRow
+			case key.Matches(msg, m.tableDataModel.keys.ToggleGrid):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					m.tableDataModel.showGrid = !m.tableDataModel.showGrid
+					if m.tableDataModel.showGrid {
+						m.tableDataModel.refreshGrid()
 					}
+					return m, nil
 				}
-			}
-		}
 
-		m.tableDataModel.dataList, cmd = m.tableDataModel.dataList.Update(msg)
-		cmds = append(cmds, cmd)
-	}
+			case key.Matches(msg, m.tableDataModel.keys.FlattenGrid):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					// Flattening only changes the grid, so it is shown right away
+					m.tableDataModel.flattenGrid = !m.tableDataModel.flattenGrid
+					m.tableDataModel.showGrid = true
+					m.tableDataModel.gridOffset = 0
+					m.tableDataModel.refreshGrid()
+					return m, nil
+				}
 
-	if m.state == ViewingRow {
-		m.collectionsList.SetShowHelp(false)
+			case key.Matches(msg, m.tableDataModel.keys.NewestFirst):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					return m, m.tableDataModel.resolveSortKey(m.tableDataModel.selectedTable)
+				}
 
-		switch msg := msg.(type) {
-		case tea.KeyMsg:
-			switch {
-			case key.Matches(msg, m.keys.ViewMode):
-				m.state = ViewingData
-				return m, nil
-			case key.Matches(msg, m.viewRowModel.keys.Down):
-				m.viewport.ViewDown()
-				return m, nil
-			case key.Matches(msg, m.viewRowModel.keys.Up):
-				m.viewport.ViewUp()
-				return m, nil
-			}
-		}
+			case key.Matches(msg, m.tableDataModel.keys.Sort):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					cmd := m.openPrompt(sortPrompt, "Sort by attribute (prefix - for descending, tab completes):")
+					m.completeAttributes()
+					return m, cmd
+				}
 
-		m.viewport, cmd = m.viewport.Update(msg)
-		cmds = append(cmds, cmd)
-	}
+			case key.Matches(msg, m.tableDataModel.keys.FilterBy):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					cmd := m.openPrompt(filterAttributePrompt, "Filter on attribute (empty for whole row, tab completes):")
+					m.completeAttributes()
+					return m, cmd
+				}
 
-	m.loadingIndicator, cmd = m.loadingIndicator.Update(msg)
-	cmds = append(cmds, cmd)
+			case key.Matches(msg, m.tableDataModel.keys.ScanFilter):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					cmd := m.openPrompt(scanFilterPrompt, `Scan filter (e.g. status = "active" and attribute_exists(deletedAt)):`)
+					m.prompt.SetValue(m.tableDataModel.scanFilterInput)
+					m.prompt.CursorEnd()
+					m.completeAttributes()
+					return m, cmd
+				}
 
-	return m, tea.Batch(cmds...)
-}
+			case key.Matches(msg, m.tableDataModel.keys.Wrap):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && !m.tableDataModel.showGrid {
+					m.tableDataModel.toggleWrap()
+					return m, nil
+				}
 
-func (m MainModel) View() string {
-	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+			case key.Matches(msg, m.tableDataModel.keys.Mark):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					m.tableDataModel.toggleMark()
+					return m, nil
+				}
 
-	if err != nil {
-		fmt.Println("Error getting terminal size:", err)
-		return ""
-	}
+			case key.Matches(msg, m.tableDataModel.keys.ClearMarks):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					m.tableDataModel.setMarked(nil)
+					return m, nil
+				}
 
-	leftWidth := int(0.3 * float64(width))
+			case key.Matches(msg, m.tableDataModel.keys.Diff):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					m.openDiff()
+					return m, nil
+				}
 
-	m.collectionsList.SetWidth(leftWidth - 5)
+			case key.Matches(msg, m.tableDataModel.keys.CopyMarked):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					m.copyMarkedRows()
+					return m, nil
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.Query):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					m.tableDataModel.lookingUp = true
+					return m, tea.Batch(m.tableDataModel.loadKeySchema(m.tableDataModel.selectedTable, true), m.tableDataModel.loadingIndicator.Tick)
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.NextPage):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.query != nil {
+					if !m.tableDataModel.query.more() {
+						m.notice = "No more pages in this partition"
+						return m, nil
+					}
+					return m, m.tableDataModel.nextQueryPage()
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.GetItem):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					m.tableDataModel.lookingUp = true
+					return m, tea.Batch(m.tableDataModel.loadKeySchema(m.tableDataModel.selectedTable, false), m.tableDataModel.loadingIndicator.Tick)
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.ScanIndex):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					m.tableDataModel.lookingUp = true
+					return m, tea.Batch(m.tableDataModel.loadIndexes(m.tableDataModel.selectedTable), m.tableDataModel.loadingIndicator.Tick)
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.NewItem):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					if !m.allowWrite() {
+						return m, nil
+					}
+					m.tableDataModel.loading = true
+					return m, tea.Batch(m.tableDataModel.fetchNewItemTemplate(m.tableDataModel.selectedTable), m.tableDataModel.loadingIndicator.Tick)
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.DeleteFiltered):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					if !m.allowRowWrite() {
+						return m, nil
+					}
+					// Marked rows are deleted first; otherwise the rows a filter
+					// shows. Without either the shown rows are the whole table.
+					if marked := m.tableDataModel.markedRows(); len(marked) > 0 {
+						m.pendingDelete = marked
+					} else if m.tableDataModel.dataList.FilterState() != list.FilterApplied && m.tableDataModel.scanFilter == nil {
+						m.notice = "Mark rows (space) or filter them (/ or F) first — X deletes only those"
+						return m, nil
+					} else {
+						m.pendingDelete = listItemsToRows(m.tableDataModel.dataList.VisibleItems())
+					}
+					if len(m.pendingDelete) > 0 {
+						message := fmt.Sprintf("Delete %d rows from %s?\nThis can't be undone. Type the table name to confirm.", len(m.pendingDelete), m.tableDataModel.selectedTable)
+						return m, m.tableDataModel.previewDelete(batchDeleteConfirmID, message, m.tableDataModel.selectedTable, m.pendingDelete)
+					}
+					return m, nil
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.Import):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					if !m.allowWrite() {
+						return m, nil
+					}
+					return m, m.openPrompt(importPrompt, "Import JSON/JSONL file:")
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.Live):
+				if m.tableDataModel.query != nil && m.tableDataModel.liveInterval == 0 {
+					m.notice = "Live refresh scans the table — end the query first (Q, then enter)"
+					return m, nil
+				}
+				if m.tableDataModel.selectedTable != "" {
+					return m, m.tableDataModel.toggleLive()
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.Stream):
+				if arn := m.tableDataModel.streamArn(); !(m.tableDataModel.dataList.FilterState() == list.Filtering) && arn != "" {
+					return m, m.openStream(arn)
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.LiveFaster):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					return m, m.tableDataModel.adjustLive(-liveIntervalStep)
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.LiveSlower):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					return m, m.tableDataModel.adjustLive(liveIntervalStep)
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.Schema):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					if len(m.tableDataModel.dataList.Items()) == 0 {
+						m.notice = "No rows loaded to infer a schema from"
+						return m, nil
+					}
+					m.openSchema()
+					return m, nil
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.CopyCLI):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					m.copyText(m.tableDataModel.scanCLICommand(m.profile, m.roleArn), "AWS CLI command")
+					return m, nil
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.Sample):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					m.tableDataModel.sampling = !m.tableDataModel.sampling
+					m.notice = "Sample mode off — scanning the whole table"
+					if m.tableDataModel.sampling {
+						m.notice = fmt.Sprintf("Sample mode on — scanning the first %s items", formatCount(m.tableDataModel.sampleSize))
+					}
+					m.tableDataModel.loading = true
+					m.tableDataModel.dataLoaded = false
+					cmds = append(cmds, m.tableDataModel.dataList.SetItems(nil))
+					return m, tea.Batch(append(cmds, m.tableDataModel.startFetch(m.tableDataModel.selectedTable, false), m.tableDataModel.loadingIndicator.Tick)...)
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.Refresh):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.query != nil {
+					return m, m.tableDataModel.restartLoad()
+				}
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					m.tableDataModel.loading = true
+					m.tableDataModel.dataLoaded = false
+					cmds = append(cmds, m.tableDataModel.dataList.SetItems(nil))
+					return m, tea.Batch(append(cmds, m.tableDataModel.startRefresh(m.tableDataModel.selectedTable), m.tableDataModel.loadingIndicator.Tick)...)
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.Rescan):
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.query != nil {
+					// A query's pages are read one at a time, so it starts over
+					return m, m.tableDataModel.restartLoad()
+				}
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) && m.tableDataModel.selectedTable != "" {
+					m.tableDataModel.loading = true
+					return m, tea.Batch(m.tableDataModel.startRescan(m.tableDataModel.selectedTable), m.tableDataModel.loadingIndicator.Tick)
+				}
+
+			case key.Matches(msg, m.tableDataModel.keys.SelectRow):
+				if m.tableDataModel.showGrid {
+					if i, ok := m.tableDataModel.selectedGridRow(); ok {
+						m.openRow(string(i))
+					}
+					return m, nil
+				}
+
+				if !(m.tableDataModel.dataList.FilterState() == list.Filtering) {
+					i, ok := m.tableDataModel.dataList.SelectedItem().(tableDataRow)
+					if ok {
+						m.openRow(string(i))
+					}
+				}
+			}
+		}
+
+		if m.tableDataModel.showGrid {
+			m.tableDataModel.grid, cmd = m.tableDataModel.grid.Update(msg)
+		} else {
+			m.tableDataModel.dataList, cmd = m.tableDataModel.dataList.Update(msg)
+		}
+		cmds = append(cmds, cmd)
+	}
+
+	if m.state == ViewingRow {
+		m.collectionsList.SetShowHelp(false)
+
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.keys.ViewMode):
+				m.state = ViewingData
+				return m, nil
+			case m.viewRowModel.tree != nil && m.viewRowModel.treeView &&
+				key.Matches(msg, m.viewRowModel.keys.Up, m.viewRowModel.keys.Down, m.viewRowModel.keys.Expand, m.viewRowModel.keys.Collapse, m.viewRowModel.keys.Fold):
+				switch {
+				case key.Matches(msg, m.viewRowModel.keys.Up):
+					m.viewRowModel.tree.move(-1)
+				case key.Matches(msg, m.viewRowModel.keys.Down):
+					m.viewRowModel.tree.move(1)
+				case key.Matches(msg, m.viewRowModel.keys.Expand):
+					m.viewRowModel.tree.expand()
+				case key.Matches(msg, m.viewRowModel.keys.Collapse):
+					m.viewRowModel.tree.collapse()
+				default:
+					m.viewRowModel.tree.toggle()
+				}
+				m.renderTree()
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.ExpandJSON):
+				m.viewRowModel.expandJSON = !m.viewRowModel.expandJSON
+				m.renderRow(m.displayedRow())
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.TreeView):
+				m.viewRowModel.treeView = !m.viewRowModel.treeView
+				m.renderRow(m.displayedRow())
+				m.viewport.GotoTop()
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.Down):
+				m.viewport.LineDown(1)
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.Up):
+				m.viewport.LineUp(1)
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.PageDown):
+				m.viewport.ViewDown()
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.PageUp):
+				m.viewport.ViewUp()
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.HalfPageDown):
+				m.viewport.HalfViewDown()
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.HalfPageUp):
+				m.viewport.HalfViewUp()
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.Top):
+				m.viewport.GotoTop()
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.Bottom):
+				m.viewport.GotoBottom()
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.NextAttr), key.Matches(msg, m.viewRowModel.keys.PrevAttr):
+				delta := 1
+				if key.Matches(msg, m.viewRowModel.keys.PrevAttr) {
+					delta = -1
+				}
+				if attribute, ok := m.viewRowModel.moveSelection(delta); ok {
+					value, _ := truncateToWidth(strings.ReplaceAll(attribute.value, "\n", " "), 40)
+					m.notice = fmt.Sprintf("%s: %s — y to copy", attribute.name, value)
+				}
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.CopyValue):
+				if attribute, ok := m.viewRowModel.selectedAttribute(); ok {
+					m.copyText(attribute.value, attribute.name)
+				} else {
+					m.notice = "Press tab to select an attribute first"
+				}
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.CopyRow):
+				m.copyText(m.tableDataModel.selectedRow, "row JSON")
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.FollowRef):
+				return m, m.followReference()
+			case key.Matches(msg, m.viewRowModel.keys.CopyStruct):
+				source, typed, err := m.viewRowModel.goStruct(m.tableDataModel.selectedTable, m.tableDataModel.selectedRow)
+				if err != nil {
+					m.lastErr = fmt.Errorf("couldn't generate a Go struct: %w", err)
+					return m, nil
+				}
+				what := "Go struct"
+				if !typed {
+					what += " (types guessed from JSON, press t first for exact ones)"
+				}
+				m.copyText(source, what)
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.ExternalEdit):
+				if !m.allowRowWrite() {
+					return m, nil
+				}
+				m.tableDataModel.lookingUp = true
+				return m, tea.Batch(m.tableDataModel.fetchEditableItem(m.tableDataModel.selectedTable, m.tableDataModel.selectedRow), m.tableDataModel.loadingIndicator.Tick)
+			case key.Matches(msg, m.viewRowModel.keys.SetAttr):
+				if !m.allowWrite() {
+					return m, nil
+				}
+				return m, m.openSetAttribute()
+			case key.Matches(msg, m.viewRowModel.keys.RemoveAttr):
+				if !m.allowWrite() {
+					return m, nil
+				}
+				return m, m.confirmRemoveAttribute()
+			case key.Matches(msg, m.viewRowModel.keys.ExtractPath):
+				return m, m.openPrompt(pathPrompt, "Path (e.g. address.city, empty for whole row):")
+			case key.Matches(msg, m.viewRowModel.keys.ToggleTypes):
+				return m, m.toggleRowTypes()
+			case key.Matches(msg, m.viewRowModel.keys.Minify):
+				m.viewRowModel.minified = !m.viewRowModel.minified
+				m.renderRow(m.displayedRow())
+				return m, nil
+			case key.Matches(msg, m.viewRowModel.keys.RenderFull):
+				m.viewRowModel.renderFull = true
+				m.renderRow(m.displayedRow())
+				return m, nil
+			}
+		}
+
+		m.viewport, cmd = m.viewport.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if m.state == EditingItem {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.editItemModel.keys.Cancel):
+				m.editItemModel.close()
+				m.state = ViewingData
+				return m, nil
+			case key.Matches(msg, m.editItemModel.keys.Save):
+				obj, err := m.editItemModel.validate()
+				m.editItemModel.err = err
+				if err == nil && m.allowWrite() {
+					m.confirmPut("", obj, fmt.Sprintf("Put the new item into %s?", m.tableDataModel.selectedTable))
+				}
+				return m, nil
+			}
+		}
+
+		cmds = append(cmds, m.editItemModel.update(msg))
+	}
+
+	if m.state == ViewingCaches {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if m.viewCachesModel.list.FilterState() != list.Filtering {
+				switch {
+				case key.Matches(msg, m.viewCachesModel.keys.Quit):
+					return m, tea.Quit
+				case key.Matches(msg, m.viewCachesModel.keys.Close) && m.viewCachesModel.list.FilterState() == list.Unfiltered:
+					m.state = m.viewCachesModel.previous
+					return m, nil
+				case key.Matches(msg, m.viewCachesModel.keys.Delete):
+					if item := m.viewCachesModel.list.SelectedItem(); item != nil {
+						m.confirmDeleteCaches([]list.Item{item})
+					}
+					return m, nil
+				case key.Matches(msg, m.viewCachesModel.keys.DeleteAll):
+					m.confirmDeleteCaches(m.viewCachesModel.list.VisibleItems())
+					return m, nil
+				}
+			}
+		}
+
+		m.viewCachesModel.list, cmd = m.viewCachesModel.list.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if m.state == ViewingSchema {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.viewSchemaModel.keys.Close):
+				m.state = ViewingData
+				return m, nil
+			case key.Matches(msg, m.viewSchemaModel.keys.Top):
+				m.schemaViewport.GotoTop()
+				return m, nil
+			case key.Matches(msg, m.viewSchemaModel.keys.Bottom):
+				m.schemaViewport.GotoBottom()
+				return m, nil
+			case key.Matches(msg, m.viewSchemaModel.keys.Summary):
+				m.viewSchemaModel.summary = !m.viewSchemaModel.summary
+				m.openSchema()
+				return m, nil
+			}
+		}
+
+		m.schemaViewport, cmd = m.schemaViewport.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if m.state == ViewingDiff {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.viewDiffModel.keys.Close):
+				m.state = ViewingData
+				return m, nil
+			case key.Matches(msg, m.viewDiffModel.keys.Top):
+				m.diffViewport.GotoTop()
+				return m, nil
+			case key.Matches(msg, m.viewDiffModel.keys.Bottom):
+				m.diffViewport.GotoBottom()
+				return m, nil
+			}
+		}
+
+		m.diffViewport, cmd = m.diffViewport.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if m.state == ViewingError {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.viewErrorModel.keys.Close):
+				m.state = m.viewErrorModel.previousState
+				return m, nil
+			case key.Matches(msg, m.viewErrorModel.keys.Copy):
+				m.copyText(m.viewErrorModel.details, "error details")
+				return m, nil
+			}
+		}
+
+		m.errorViewport, cmd = m.errorViewport.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if m.state == ViewingStream {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.viewStreamModel.keys.Close):
+				m.state = m.viewStreamModel.previousState
+				m.notice = "Stopped tailing the stream"
+				return m, nil
+			case key.Matches(msg, m.viewStreamModel.keys.Clear):
+				m.viewStreamModel.records = nil
+				m.refreshStream()
+				return m, nil
+			case key.Matches(msg, m.viewStreamModel.keys.Top):
+				m.streamViewport.GotoTop()
+				return m, nil
+			case key.Matches(msg, m.viewStreamModel.keys.Bottom):
+				m.streamViewport.GotoBottom()
+				return m, nil
+			}
+		}
+
+		m.streamViewport, cmd = m.streamViewport.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if m.state == ViewingLogs {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.viewLogsModel.keys.Close):
+				m.state = m.viewLogsModel.previousState
+				return m, nil
+			case key.Matches(msg, m.viewLogsModel.keys.Top):
+				m.logsViewport.GotoTop()
+				return m, nil
+			case key.Matches(msg, m.viewLogsModel.keys.Bottom):
+				m.logsViewport.GotoBottom()
+				return m, nil
+			}
+		}
+
+		m.logsViewport, cmd = m.logsViewport.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	m.loadingIndicator, cmd = m.loadingIndicator.Update(msg)
+	cmds = append(cmds, cmd)
+
+	m.tableDataModel.loadingIndicator, cmd = m.tableDataModel.loadingIndicator.Update(msg)
+	cmds = append(cmds, cmd)
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m MainModel) View() string {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+
+	if err != nil {
+		fmt.Println("Error getting terminal size:", err)
+		return ""
+	}
+
+	// Below the minimum the layout math goes negative, so don't attempt it
+	if m.termWidth > 0 && (m.termWidth < minTerminalWidth || m.termHeight < minTerminalHeight) {
+		message := fmt.Sprintf("Terminal too small — please resize\n(%dx%d, need at least %dx%d)", m.termWidth, m.termHeight, minTerminalWidth, minTerminalHeight)
+		return lipgloss.Place(m.termWidth, m.termHeight, lipgloss.Center, lipgloss.Center, message)
+	}
+
+	if m.confirmDialog.Active() {
+		return m.confirmDialog.View(width, height)
+	}
+
+	if m.helpOverlay.Active() {
+		return m.helpOverlay.View(width, height)
+	}
+
+	leftWidth := m.leftPaneWidth(width)
+
+	m.collectionsList.SetWidth(leftWidth - 5)
 
 	m.tableDataModel.dataList.SetWidth(width - leftWidth - 10)
+	m.tableDataModel.grid.SetWidth(width - leftWidth - 10)
+	m.editItemModel.editor.SetWidth(width - leftWidth - 10)
+	m.editItemModel.editor.SetHeight(height - 15) // One line for the JSON check
 
 	var s string
 
@@ -422,46 +1753,378 @@ func (m MainModel) View() string {
 
 	helpView := m.help.View(m.keys)
 
+	collectionsContent := m.collectionsList.View()
+	if len(m.collectionsList.Items()) == 0 {
+		emptyMessage := "Fetching tables..."
+		if m.collectionsReady && !m.loading {
+			emptyMessage = fmt.Sprintf("No tables found in %s — press r to switch region, R to refresh.", m.regionKey())
+		}
+		collectionsContent = lipgloss.NewStyle().Width(leftWidth - 5).Render(emptyMessage)
+	}
+
 	dataContent := m.tableDataModel.dataList.View()
+	if m.tableDataModel.showGrid {
+		dataContent = m.tableDataModel.grid.View()
+	}
+	if emptyMessage := m.dataEmptyMessage(); emptyMessage != "" {
+		emptyView := lipgloss.Place(width-leftWidth-10, height-10, lipgloss.Center, lipgloss.Center, emptyMessage)
+		if m.tableDataModel.dataList.FilterState() == list.Filtering {
+			// Keep the filter input visible while the user is typing
+			emptyView = m.tableDataModel.dataList.View() + "\n" + emptyMessage
+		}
+		dataContent = emptyView
+	}
+
+	dataLabel := "Data"
+	if count := m.tableDataModel.countLabel(); count != "" {
+		dataLabel += " — " + count
+	}
 
 	switch m.state {
 	case ViewingData:
-		helpView = m.help.View(m.tableDataModel.keys)
+		helpView = m.help.View(legendFor(m.tableDataModel.dataList, m.tableDataModel.keys))
 		tableDataPane = components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
 	case ViewingCollections:
 		tableListPane = components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
 	case ViewingRow:
 		helpView = m.help.View(m.viewRowModel.keys)
 		tableDataPane = components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
+		if m.viewRowModel.showTypes {
+			// The item as DynamoDB sends it, re-read by its key
+			dataLabel = "Row — raw AttributeValue JSON"
+		}
 
 		dataContent = m.viewport.View()
+	case EditingItem:
+		helpView = m.help.View(m.editItemModel.keys)
+		tableDataPane = components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
+
+		dataContent = m.editItemModel.View()
+	case ViewingLogs:
+		helpView = m.help.View(m.viewLogsModel.keys)
+		tableDataPane = components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
+
+		dataContent = m.logsViewport.View()
+	case ViewingCaches:
+		helpView = m.help.View(legendFor(m.viewCachesModel.list, m.viewCachesModel.keys))
+		tableDataPane = components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
+
+		dataLabel = m.viewCachesModel.label()
+		m.viewCachesModel.list.SetSize(width-leftWidth-10, height-10)
+		dataContent = m.viewCachesModel.list.View()
+		if len(m.viewCachesModel.list.Items()) == 0 {
+			dataContent = lipgloss.Place(width-leftWidth-10, height-10, lipgloss.Center, lipgloss.Center, "No cache files")
+		}
+	case ViewingSchema:
+		helpView = m.help.View(m.viewSchemaModel.keys)
+		tableDataPane = components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
+
+		dataLabel = "Schema — " + m.tableDataModel.selectedTable
+		if m.viewSchemaModel.summary {
+			dataLabel = "Summary — " + m.tableDataModel.selectedTable
+		}
+		dataContent = m.schemaViewport.View()
+	case ViewingDiff:
+		helpView = m.help.View(m.viewDiffModel.keys)
+		tableDataPane = components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
+
+		dataLabel = "Diff — " + m.tableDataModel.selectedTable
+		dataContent = m.diffViewport.View()
+	case ViewingError:
+		helpView = m.help.View(m.viewErrorModel.keys)
+		tableDataPane = components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
+
+		dataLabel = "Error details"
+		dataContent = m.errorViewport.View()
+	case ViewingStream:
+		helpView = m.help.View(m.viewStreamModel.keys)
+		tableDataPane = components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
+
+		dataLabel = fmt.Sprintf("Stream — %s, %s records", m.viewStreamModel.table, formatCount(len(m.viewStreamModel.records)))
+		dataContent = m.streamViewport.View()
+	}
+
+	leftPanes := []string{
+		awsRegionPane.Render("AWS Region", m.regionKey(), leftWidth, 3),
+		tableListPane.Render(paneLabel(m.collectionsLabel(), m.loading, m.loadingIndicator), collectionsContent, leftWidth, height-11),
+	}
+	if m.hideRegionPane {
+		leftPanes = []string{tableListPane.Render(paneLabel(m.collectionsLabel(), m.loading, m.loadingIndicator), collectionsContent, leftWidth, height-11+regionPaneHeight)}
 	}
 
 	s += lipgloss.JoinHorizontal(
 		lipgloss.Top,
-		lipgloss.JoinVertical(
-			lipgloss.Top,
-			awsRegionPane.Render("AWS Region", m.region, leftWidth, 3),
-			tableListPane.Render("Collections", m.collectionsList.View(), leftWidth, height-11),
-		),
-		tableDataPane.Render("Data", dataContent, width-leftWidth-4, height-6),
+		lipgloss.JoinVertical(lipgloss.Top, leftPanes...),
+		tableDataPane.Render(paneLabel(dataLabel, m.tableDataModel.loading || m.tableDataModel.lookingUp, m.tableDataModel.loadingIndicator), dataContent, width-leftWidth-4, height-6),
 	)
 
-	loadingFeedback := m.loadingIndicator.View()
-
-	if !m.loading {
-		loadingFeedback = ""
+	if m.lastErr != nil {
+		s += "\n" + m.renderErrorBanner(width)
 	}
 
-	s += lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true).Render("\n" + m.GetCurrentState() + " " + loadingFeedback + "\n")
+	s += "\n" + m.renderStatusBar(width) + "\n"
 
-	if m.state != ViewingCollections {
+	if m.promptKind != noPrompt {
+		s += "\n" + m.prompt.View() + m.completionsView()
+	} else if m.state != ViewingCollections {
 		s += "\n" + helpView
 	}
 
 	return s
 }
 
+// collectionsLabel names the collections pane, marking it while cached
+// collections are being refreshed
+func (m MainModel) collectionsLabel() string {
+	label := fmt.Sprintf("Collections (%s)", m.filterMode)
+	if m.refreshingCached {
+		label += " ⟳"
+	}
+	return label
+}
+
+// paneLabel appends the pane's spinner to its label while it is loading
+func paneLabel(label string, loading bool, indicator spinner.Model) string {
+	if !loading {
+		return label
+	}
+	return label + " " + indicator.View()
+}
+
+// dataEmptyMessage explains why the data pane has no rows to show, or returns
+// an empty string when there is nothing to explain
+func (m MainModel) dataEmptyMessage() string {
+	if !m.tableDataModel.dataLoaded || m.tableDataModel.loading || (m.state != ViewingData && m.state != ViewMode) {
+		return ""
+	}
+
+	if len(m.tableDataModel.dataList.Items()) == 0 {
+		return "This table is empty"
+	}
+
+	if m.tableDataModel.dataList.FilterState() != list.Unfiltered && len(m.tableDataModel.dataList.VisibleItems()) == 0 {
+		return "No rows match your filter."
+	}
+
+	return ""
+}
+
+// handleConfirmation runs the action the user just confirmed
+func (m *MainModel) handleConfirmation(msg components.ConfirmResultMsg) tea.Cmd {
+	switch msg.ID {
+	case batchDeleteConfirmID:
+		rows := m.pendingDelete
+		m.pendingDelete = nil
+		if !msg.Confirmed || !m.allowRowWrite() {
+			return nil
+		}
+		m.tableDataModel.loading = true
+		return tea.Batch(m.tableDataModel.deleteRows(m.tableDataModel.selectedTable, rows), m.tableDataModel.loadingIndicator.Tick)
+	case putItemConfirmID:
+		edit := m.pendingEdit
+		m.pendingEdit = nil
+		if edit == nil || !msg.Confirmed || !m.allowWrite() {
+			return nil
+		}
+		m.tableDataModel.loading = true
+		return tea.Batch(m.tableDataModel.putItem(m.tableDataModel.selectedTable, edit.obj, edit.row), m.tableDataModel.loadingIndicator.Tick)
+	case updateItemConfirmID:
+		return m.applyUpdate(msg.Confirmed)
+	case deleteCachesConfirmID:
+		paths := m.viewCachesModel.pending
+		m.viewCachesModel.pending = nil
+		if !msg.Confirmed || len(paths) == 0 {
+			return nil
+		}
+		return deleteCaches(paths)
+	case heavyScanConfirmID:
+		return m.startHeavyScan(msg)
+	case deleteTableConfirmID:
+		pending := m.pendingTableDelete
+		m.pendingTableDelete = nil
+		if pending == nil || !msg.Confirmed || !m.allowWrite() {
+			return nil
+		}
+		m.loading = true
+		return tea.Batch(m.deleteTable(pending.region, pending.table), m.loadingIndicator.Tick)
+	}
+
+	return nil
+}
+
+// switchPane moves the focus between the collections and data panes without
+// going through view mode. The data pane only takes the focus once rows were
+// loaded. It reports whether the focus moved.
+func (m *MainModel) switchPane() bool {
+	switch {
+	case m.state == ViewingData:
+		m.state = ViewingCollections
+		m.collectionsList.SetShowHelp(true)
+	case (m.state == ViewingCollections || m.state == ViewMode) && m.tableDataModel.dataLoaded:
+		m.state = ViewingData
+	case m.state == ViewMode:
+		m.state = ViewingCollections
+		m.collectionsList.SetShowHelp(true)
+	default:
+		return false
+	}
+	return true
+}
+
+// allowWrite reports whether a write action may run. In read-only mode it
+// refuses and tells the user why. Every write must be dispatched through it.
+func (m *MainModel) allowWrite() bool {
+	if m.readOnly {
+		m.lastErr = errReadOnly
+		return false
+	}
+	return true
+}
+
+// openRow renders the given row into the viewport and switches to the row view
+func (m *MainModel) openRow(row string) {
+	m.tableDataModel.selectedRow = row
+	m.viewRowModel.showTypes = false
+	m.viewRowModel.path = ""
+	m.viewRowModel.renderFull = false
+	m.viewRowModel.setRow(row)
+	m.renderRow(row)
+	if m.tableDataModel.scanIndex != "" {
+		m.notice = fmt.Sprintf("From index %s — only %s", m.tableDataModel.scanIndex, m.tableDataModel.scanIndexProjection)
+	}
+
+	m.state = ViewingRow
+}
+
+// renderRow shows the given JSON in the row viewport, narrowed down to the
+// selected path if there is one
+func (m *MainModel) renderRow(rawJSON string) {
+	m.viewRowModel.keys.RenderFull.SetEnabled(false)
+
+	if m.viewRowModel.path != "" {
+		extracted, err := extractRowPath(rawJSON, m.viewRowModel.path)
+		if err != nil {
+			m.lastErr = err
+			m.viewRowModel.path = ""
+		} else {
+			rawJSON = extracted
+		}
+	}
+
+	if m.viewRowModel.expandJSON {
+		if expanded, err := tools.ExpandJSONStrings(rawJSON); err == nil {
+			rawJSON = expanded
+		}
+	}
+
+	if m.viewRowModel.treeView {
+		tree, err := newRowTree(rawJSON)
+		if err != nil {
+			m.viewRowModel.tree = nil
+			m.viewport.SetContent("Could not render row.")
+			return
+		}
+		m.viewRowModel.tree = tree
+		m.renderTree()
+		return
+	}
+
+	if m.viewRowModel.minified {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, []byte(rawJSON)); err != nil {
+			m.viewport.SetContent("Could not render row.")
+			return
+		}
+		m.viewport.SetContent(lipgloss.NewStyle().Width(m.viewport.Width).Render(compact.String()))
+		return
+	}
+
+	if len(rawJSON) > largeRowSize && !m.viewRowModel.renderFull {
+		m.viewRowModel.keys.RenderFull.SetEnabled(true)
+		m.viewport.SetContent(plainLargeRow(rawJSON))
+		return
+	}
+
+	dataContent, err := tools.RenderJSONWithGlamour(rawJSON, m.viewport.Width)
+	if err != nil {
+		dataContent = "Could not render row."
+	}
+
+	m.viewport.SetContent(dataContent)
+}
+
+// renderTree draws the tree view of the row, scrolling the viewport so the
+// cursor stays visible
+func (m *MainModel) renderTree() {
+	content, line := m.viewRowModel.tree.render(m.viewport.Width)
+	m.viewport.SetContent(content)
+
+	if line < m.viewport.YOffset {
+		m.viewport.SetYOffset(line)
+	} else if line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(line - m.viewport.Height + 1)
+	}
+}
+
+// displayedRow returns the JSON of the viewed row in the form currently shown,
+// typed or plain
+func (m MainModel) displayedRow() string {
+	row := m.tableDataModel.selectedRow
+	if typed, ok := m.viewRowModel.typedRows[row]; ok && m.viewRowModel.showTypes {
+		return typed
+	}
+	return row
+}
+
+// setRowPath narrows the row view down to path; an empty path shows the
+// whole row again
+func (m *MainModel) setRowPath(path string) {
+	m.viewRowModel.path = path
+	m.lastErr = nil
+
+	m.renderRow(m.displayedRow())
+	m.viewport.GotoTop()
+}
+
+// extractRowPath returns the JSON found at path in the row
+func extractRowPath(rawJSON, path string) (string, error) {
+	obj, err := tools.ParseItemJSON(rawJSON)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := tools.ExtractPath(obj, path)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// toggleRowTypes switches the row view between plain values and values
+// annotated with their DynamoDB type, fetching the typed item when needed
+func (m *MainModel) toggleRowTypes() tea.Cmd {
+	row := m.tableDataModel.selectedRow
+	m.viewRowModel.showTypes = !m.viewRowModel.showTypes
+
+	if !m.viewRowModel.showTypes {
+		m.renderRow(row)
+		return nil
+	}
+
+	if typed, ok := m.viewRowModel.typedRows[row]; ok {
+		m.renderRow(typed)
+		return nil
+	}
+
+	m.tableDataModel.lookingUp = true
+	return tea.Batch(m.tableDataModel.fetchTypedRow(m.tableDataModel.selectedTable, row), m.tableDataModel.loadingIndicator.Tick)
+}
+
 func (m MainModel) GetCurrentState() string {
 	switch m.state {
 	case ViewMode:
@@ -472,13 +2135,27 @@ func (m MainModel) GetCurrentState() string {
 		return "View Row"
 	case ViewingCollections:
 		return "View Collections"
+	case EditingItem:
+		return "Edit Item"
+	case ViewingLogs:
+		return "View Logs"
+	case ViewingSchema:
+		return "View Schema"
+	case ViewingDiff:
+		return "View Diff"
+	case ViewingCaches:
+		return "View Caches"
+	case ViewingError:
+		return "View Error"
+	case ViewingStream:
+		return "View Stream"
 	default:
 		return "View Mode"
 	}
 }
 
 func (m *MainModel) EditMode() bool {
-	return m.state == ViewingCollections || m.state == ViewingData
+	return m.state == ViewingCollections || m.state == ViewingData || m.state == EditingItem || m.state == ViewingCaches
 }
 
 type TablesFetchStartedMsg string
@@ -492,57 +2169,63 @@ func (m MainModel) startCollectionsFetch() tea.Cmd {
 // fetchCollections with cache fallback and fetch if cache is missing
 func (m MainModel) fetchCollections() tea.Cmd {
 	return func() tea.Msg {
-		// Attempt to load cached data
-		cache, err := tools.LoadCache(CollectionsCacheFilePath)
-		if err == nil && time.Since(cache.Updated) < CacheDuration {
-			// Return cached data immediately
-			go m.refreshCacheInBackground() // Trigger background fetch in the background
+		// Attempt to load cached data; return it immediately and let the
+		// handler trigger a background refresh
+		var items []list.Item
+		for _, region := range m.activeRegions() {
+			cache, err := tools.LoadCache(collectionsCacheFilePath(region))
+			if err != nil || time.Since(cache.Updated) >= CacheDuration {
+				// If a cache is missing or outdated, fetch data and cache it
+				return m.fetchAndCacheCollections()
+			}
 
 			// Convert cached data to list.Item
-			var items []list.Item
 			for _, value := range cache.Data {
-				items = append(items, tableNameItem(value))
+				items = append(items, m.collectionItem(region, value))
 			}
-			return TablesFetchedMsg(items)
 		}
 
-		// If cache is missing or outdated, fetch data and cache it
+		return TablesFetchedMsg{region: m.regionKey(), items: items, fromCache: true}
+	}
+}
+
+// refreshCollections fetches the collections from DynamoDB, bypassing the cache
+func (m MainModel) refreshCollections() tea.Cmd {
+	return func() tea.Msg {
 		return m.fetchAndCacheCollections()
 	}
 }
 
+// Helper function to generate a unique cache file path for each region's collections
+func collectionsCacheFilePath(region string) string {
+	return filepath.Join(CacheDir, region+"_collections_cache.json")
+}
+
 // fetchAndCacheCollections performs an immediate fetch from DynamoDB and caches the result
 func (m MainModel) fetchAndCacheCollections() tea.Msg {
-	var tableNames []list.Item
-	input := &dynamodb.ListTablesInput{}
-	paginator := dynamodb.NewListTablesPaginator(m.client, input)
-
-	// Fetch table names from DynamoDB
-	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
-		if err != nil {
-			return FetchErrorMsg{err}
-		}
-		for _, tableName := range page.TableNames {
-			tableNames = append(tableNames, tableNameItem(tableName))
+	var items []list.Item
+	for _, region := range m.activeRegions() {
+		var tableNames []list.Item
+		input := &dynamodb.ListTablesInput{}
+		paginator := dynamodb.NewListTablesPaginator(m.clientFor(region), input)
+
+		// Fetch table names from DynamoDB
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.TODO())
+			if err != nil {
+				return FetchErrorMsg{err}
+			}
+			for _, tableName := range page.TableNames {
+				tableNames = append(tableNames, tableNameItem(tableName))
+				items = append(items, m.collectionItem(region, tableName))
+			}
 		}
-	}
 
-	// Cache the fetched data
-	if err := tools.SaveCache(tableNames, CacheDir, CollectionsCacheFilePath); err != nil {
-		log.Println("Failed to save cache:", err)
+		// Cache the fetched data; each region keeps its own cache
+		if err := tools.SaveCache(tableNames, CacheDir, collectionsCacheFilePath(region)); err != nil {
+			log.Println("Failed to save cache:", err)
+		}
 	}
 
-	return TablesFetchedMsg(tableNames)
-}
-
-// refreshCacheInBackground fetches fresh data and updates the cache in the background
-func (m MainModel) refreshCacheInBackground() {
-	// Perform a fetch and cache update in the background
-	msg := m.fetchAndCacheCollections()
-	if fetchMsg, ok := msg.(TablesFetchedMsg); ok {
-		// Handle the result if needed (e.g., update the UI with fresh data)
-		// This step is optional depending on your app's needs
-		log.Println("Cache refreshed in background:", fetchMsg)
-	}
+	return TablesFetchedMsg{region: m.regionKey(), items: items}
 }
@@ -0,0 +1,189 @@
+package lazydynamo
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const deleteCachesConfirmID = "delete-caches"
+
+// CachesListedMsg carries the cache files found in the cache directory
+type CachesListedMsg struct {
+	caches []tools.CacheInfo
+	err    error
+}
+
+// CachesDeletedMsg reports how many cache files were deleted
+type CachesDeletedMsg struct {
+	deleted int
+	err     error
+}
+
+// cacheItem is a cache file in the caches list
+type cacheItem tools.CacheInfo
+
+func (i cacheItem) Title() string       { return i.Name }
+func (i cacheItem) Description() string { return formatBytes(i.Size) + " · " + formatAge(i.Modified) }
+func (i cacheItem) FilterValue() string { return i.Name }
+
+type ViewCachesKeyMap struct {
+	Delete    key.Binding
+	DeleteAll key.Binding
+	Close     key.Binding
+	Help      key.Binding
+	Quit      key.Binding
+}
+
+func (k ViewCachesKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Delete, k.DeleteAll, k.Close, k.Help, k.Quit}
+}
+
+func (k ViewCachesKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Delete, k.DeleteAll},
+		{k.Close},
+		{k.Help, k.Quit},
+	}
+}
+
+var viewCachesKeys = ViewCachesKeyMap{
+	Delete: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "delete cache"),
+	),
+	DeleteAll: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "delete listed caches"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "close caches"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "all keys"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+type ViewCachesModel struct {
+	keys     ViewCachesKeyMap
+	list     list.Model
+	total    int64    // bytes used by all cache files
+	pending  []string // caches waiting for the delete confirmation
+	previous sessionState
+}
+
+func (m ViewCachesModel) New() ViewCachesModel {
+	l := list.New(nil, list.NewDefaultDelegate(), 10, 10)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.Styles.PaginationStyle = paginationStyle
+	// esc closes the caches and q is handled like everywhere else
+	l.KeyMap.Quit.SetEnabled(false)
+
+	return ViewCachesModel{
+		keys: viewCachesKeys,
+		list: l,
+	}
+}
+
+// label names the caches pane with the number of files and their total size
+func (m ViewCachesModel) label() string {
+	return fmt.Sprintf("Caches — %d files, %s", len(m.list.Items()), formatBytes(m.total))
+}
+
+// listCaches reads the cache files in the cache directory
+func listCaches() tea.Cmd {
+	return func() tea.Msg {
+		caches, err := tools.ListCaches(CacheDir)
+		return CachesListedMsg{caches: caches, err: err}
+	}
+}
+
+// setCaches shows the listed cache files
+func (m *ViewCachesModel) setCaches(caches []tools.CacheInfo) tea.Cmd {
+	items := make([]list.Item, len(caches))
+	m.total = 0
+	for i, cache := range caches {
+		items[i] = cacheItem(cache)
+		m.total += cache.Size
+	}
+	return m.list.SetItems(items)
+}
+
+// confirmDeleteCaches asks before deleting the given caches. Deleting a cache
+// only means the table is scanned again next time, so a y/n is enough.
+func (m *MainModel) confirmDeleteCaches(caches []list.Item) {
+	var size int64
+	m.viewCachesModel.pending = nil
+	for _, item := range caches {
+		if cache, ok := item.(cacheItem); ok {
+			m.viewCachesModel.pending = append(m.viewCachesModel.pending, cache.Path)
+			size += cache.Size
+		}
+	}
+	if len(m.viewCachesModel.pending) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Delete %d cache files (%s)?", len(m.viewCachesModel.pending), formatBytes(size))
+	if len(caches) == 1 {
+		message = fmt.Sprintf("Delete %s (%s)?", caches[0].(cacheItem).Name, formatBytes(size))
+	}
+	m.confirmDialog = m.confirmDialog.Open(deleteCachesConfirmID, message)
+}
+
+// deleteCaches deletes the given cache files and lists the rest again
+func deleteCaches(paths []string) tea.Cmd {
+	return func() tea.Msg {
+		deleted := 0
+		for _, path := range paths {
+			if err := tools.DeleteCache(path); err != nil {
+				log.Printf("Failed to delete cache %s: %v", path, err)
+				return CachesDeletedMsg{deleted: deleted, err: err}
+			}
+			deleted++
+		}
+		return CachesDeletedMsg{deleted: deleted}
+	}
+}
+
+// formatBytes renders a size such as "1.5 MB"
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exponent := float64(size)/unit, 0
+	for value >= unit && exponent < 3 {
+		value /= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "KMGT"[exponent])
+}
+
+// formatAge renders how long ago t was, e.g. "3h ago"
+func formatAge(t time.Time) string {
+	age := time.Since(t)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
@@ -0,0 +1,80 @@
+package lazydynamo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markMarker is shown before marked rows in the data list
+const markMarker = "* "
+
+// toggleMark marks or unmarks the row under the cursor and moves down, so a
+// run of rows can be marked by holding space
+func (m *TableDataModel) toggleMark() {
+	var row string
+	if m.showGrid {
+		selected, ok := m.selectedGridRow()
+		if !ok {
+			return
+		}
+		row = string(selected)
+	} else {
+		selected, ok := m.dataList.SelectedItem().(tableDataRow)
+		if !ok {
+			return
+		}
+		row = string(selected)
+	}
+
+	// A fresh map, so earlier copies of the model keep their marks
+	marked := make(map[string]bool, len(m.marked)+1)
+	for r := range m.marked {
+		marked[r] = true
+	}
+	if marked[row] {
+		delete(marked, row)
+	} else {
+		marked[row] = true
+	}
+	m.setMarked(marked)
+
+	if m.showGrid {
+		m.grid.MoveDown(1)
+	} else {
+		m.dataList.CursorDown()
+	}
+}
+
+// setMarked replaces the marked rows and redraws the list with their markers
+func (m *TableDataModel) setMarked(marked map[string]bool) {
+	if len(marked) == 0 {
+		marked = nil
+	}
+	m.marked = marked
+	m.refreshDelegate()
+}
+
+// markedRows returns the marked rows that are still loaded, in list order
+func (m TableDataModel) markedRows() []string {
+	if len(m.marked) == 0 {
+		return nil
+	}
+
+	var rows []string
+	for _, item := range m.dataList.Items() {
+		if m.marked[item.FilterValue()] {
+			rows = append(rows, item.FilterValue())
+		}
+	}
+	return rows
+}
+
+// copyMarkedRows copies the marked rows as a JSON array
+func (m *MainModel) copyMarkedRows() {
+	rows := m.tableDataModel.markedRows()
+	if len(rows) == 0 {
+		m.notice = "No rows marked — space marks the row under the cursor"
+		return
+	}
+	m.copyText("["+strings.Join(rows, ",")+"]", fmt.Sprintf("%d marked rows", len(rows)))
+}
@@ -0,0 +1,49 @@
+package lazydynamo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SortKeyResolvedMsg carries the sort key of a table, to order its rows by
+type SortKeyResolvedMsg struct {
+	region    string
+	table     string
+	attribute string
+}
+
+// resolveSortKey looks up the table's sort key so the loaded rows can be
+// shown newest first. The rows come from a scan, so they are sorted client
+// side: numbers such as epoch timestamps compare numerically and ISO dates
+// compare correctly as strings.
+func (m TableDataModel) resolveSortKey(tableName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			return FetchErrorMsg{err}
+		}
+
+		_, sortKey, err := extractPrimaryKeyAttributes(tableInfo.KeySchema)
+		if err != nil {
+			return FetchErrorMsg{err}
+		}
+		if sortKey == nil {
+			return FetchErrorMsg{fmt.Errorf("%s has no sort key to order by — press s to sort by an attribute", tableName)}
+		}
+
+		for _, definition := range tableInfo.AttributeDefinitions {
+			if *definition.AttributeName == *sortKey && definition.AttributeType == types.ScalarAttributeTypeB {
+				return FetchErrorMsg{fmt.Errorf("sort key %s is binary and has no meaningful order", *sortKey)}
+			}
+		}
+
+		return SortKeyResolvedMsg{region: m.region, table: tableName, attribute: *sortKey}
+	}
+}
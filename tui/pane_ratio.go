@@ -0,0 +1,50 @@
+package lazydynamo
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// DefaultPaneRatio is the share of the width the collections pane takes
+	// unless configured otherwise
+	DefaultPaneRatio = 0.3
+	MinPaneRatio     = 0.15
+	MaxPaneRatio     = 0.6
+
+	paneRatioStep = 0.05
+)
+
+// leftPaneWidth returns the width of the collections pane in a terminal of
+// the given width
+func (m MainModel) leftPaneWidth(width int) int {
+	ratio := m.paneRatio
+	if ratio == 0 {
+		ratio = DefaultPaneRatio
+	}
+	return int(ratio * float64(width))
+}
+
+// adjustPaneRatio grows or shrinks the collections pane, within bounds, and
+// lays the panes out again
+func (m *MainModel) adjustPaneRatio(delta float64) tea.Cmd {
+	if m.paneRatio == 0 {
+		m.paneRatio = DefaultPaneRatio
+	}
+	m.paneRatio = min(max(m.paneRatio+delta, MinPaneRatio), MaxPaneRatio)
+	m.notice = fmt.Sprintf("Collections pane at %.0f%%", m.paneRatio*100)
+
+	return m.relayout()
+}
+
+// relayout sizes the panes again for the current terminal
+func (m MainModel) relayout() tea.Cmd {
+	// Before the first WindowSizeMsg there is nothing to lay out yet
+	if m.termWidth == 0 {
+		return nil
+	}
+
+	width, height := m.termWidth, m.termHeight
+	return func() tea.Msg { return tea.WindowSizeMsg{Width: width, Height: height} }
+}
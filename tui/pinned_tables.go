@@ -0,0 +1,90 @@
+package lazydynamo
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pinMarker is shown before pinned tables in the collections list
+const pinMarker = "★ "
+
+// pinKey identifies a pinned table; pins are kept per region
+func pinKey(region, table string) string {
+	return region + regionSeparator + table
+}
+
+// isPinned reports whether a collections list entry is pinned
+func (m MainModel) isPinned(item tableNameItem) bool {
+	return m.pinned[pinKey(m.splitCollectionItem(item))]
+}
+
+// orderCollections moves the pinned tables to the top of the list, keeping
+// the order of both sections otherwise
+func (m MainModel) orderCollections(items []list.Item) []list.Item {
+	ordered := make([]list.Item, len(items))
+	copy(ordered, items)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, _ := ordered[i].(tableNameItem)
+		b, _ := ordered[j].(tableNameItem)
+		return m.isPinned(a) && !m.isPinned(b)
+	})
+	return ordered
+}
+
+// setCollections shows the given collections with the pinned ones first
+func (m *MainModel) setCollections(items []list.Item) tea.Cmd {
+	pinned := make(map[tableNameItem]bool)
+	for _, item := range items {
+		if i, ok := item.(tableNameItem); ok && m.isPinned(i) {
+			pinned[i] = true
+		}
+	}
+	m.collectionsList.SetDelegate(itemDelegate{tags: m.appConfig.TableTags, pinned: pinned})
+
+	return m.collectionsList.SetItems(m.orderCollections(items))
+}
+
+// togglePin pins or unpins the selected table, saves the pins and keeps the
+// cursor on the table as it moves between the sections
+func (m *MainModel) togglePin() tea.Cmd {
+	item, ok := m.collectionsList.SelectedItem().(tableNameItem)
+	if !ok {
+		return nil
+	}
+	region, table := m.splitCollectionItem(item)
+
+	// A fresh map, so earlier copies of the model keep their pins
+	pinned := make(map[string]bool, len(m.pinned)+1)
+	for key := range m.pinned {
+		pinned[key] = true
+	}
+	if pinned[pinKey(region, table)] {
+		delete(pinned, pinKey(region, table))
+		m.notice = fmt.Sprintf("Unpinned %s", table)
+	} else {
+		pinned[pinKey(region, table)] = true
+		m.notice = fmt.Sprintf("Pinned %s", table)
+	}
+
+	if err := tools.SavePinnedTables(pinned, PinnedTablesFilePath); err != nil {
+		log.Printf("Failed to save pinned tables: %v", err)
+		m.notice = ""
+		m.lastErr = fmt.Errorf("failed to save pinned tables: %w", err)
+		return nil
+	}
+	m.pinned = pinned
+
+	cmd := m.setCollections(m.collectionsList.Items())
+	for i, visible := range m.collectionsList.VisibleItems() {
+		if visible == item {
+			m.collectionsList.Select(i)
+			break
+		}
+	}
+	return cmd
+}
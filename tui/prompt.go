@@ -0,0 +1,223 @@
+package lazydynamo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxCompletionsShown is how many matching attribute names are listed next
+// to the prompt
+const maxCompletionsShown = 5
+
+var completionsStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+// promptKind identifies what a submitted prompt value is used for
+type promptKind int
+
+const (
+	noPrompt promptKind = iota
+	sortPrompt
+	regionPrompt
+	importPrompt
+	filterAttributePrompt
+	pathPrompt
+	scanFilterPrompt
+	indexPrompt
+	setAttributePrompt
+	getItemPrompt
+	queryPrompt
+)
+
+func newPrompt() textinput.Model {
+	ti := textinput.New()
+	ti.PromptStyle = spinnerStyle
+	ti.CharLimit = 256
+	// Up and down browse the prompt's history
+	ti.KeyMap.NextSuggestion = key.NewBinding(key.WithKeys("ctrl+n"))
+	ti.KeyMap.PrevSuggestion = key.NewBinding(key.WithKeys("ctrl+p"))
+
+	return ti
+}
+
+// openPrompt shows the prompt line with the given label and captures key input
+// until it is submitted or cancelled
+func (m *MainModel) openPrompt(kind promptKind, label string) tea.Cmd {
+	m.promptKind = kind
+	m.prompt.Prompt = label + " "
+	m.prompt.SetValue("")
+	m.prompt.ShowSuggestions = false
+	m.prompt.SetSuggestions(nil)
+	m.promptWords = nil
+	m.history.browsing = -1
+
+	return m.prompt.Focus()
+}
+
+func (m *MainModel) closePrompt() {
+	m.promptKind = noPrompt
+	m.prompt.Blur()
+}
+
+// updatePrompt handles key input while a prompt is open
+func (m *MainModel) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.closePrompt()
+		return nil
+	case tea.KeyEnter:
+		kind := m.promptKind
+		value := strings.TrimSpace(m.prompt.Value())
+		m.closePrompt()
+		m.recordPrompt(kind, value)
+		return m.submitPrompt(kind, value)
+	case tea.KeyUp:
+		if m.browseHistory(1) {
+			return nil
+		}
+	case tea.KeyDown:
+		if m.browseHistory(-1) {
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.prompt, cmd = m.prompt.Update(msg)
+	m.updateCompletions()
+	return cmd
+}
+
+// completeAttributes has the prompt complete the attribute names of the
+// loaded rows: tab accepts the completion shown, ctrl+n and ctrl+p cycle
+// through the others
+func (m *MainModel) completeAttributes() {
+	m.promptWords = m.tableDataModel.attributeNames()
+	m.prompt.ShowSuggestions = true
+	m.updateCompletions()
+}
+
+// updateCompletions completes the word at the end of the prompt's value
+func (m *MainModel) updateCompletions() {
+	if m.promptWords != nil {
+		m.prompt.SetSuggestions(tools.CompleteLastWord(m.prompt.Value(), m.promptWords))
+	}
+}
+
+// completionsView lists the attribute names matching the word being typed
+// next to the prompt
+func (m MainModel) completionsView() string {
+	word := tools.LastWord(m.prompt.Value())
+	if m.promptWords == nil || word == "" {
+		return ""
+	}
+
+	matches := tools.MatchWords(word, m.promptWords)
+	if len(matches) == 0 || len(matches) == 1 && matches[0] == word {
+		return ""
+	}
+	view := strings.Join(matches[:min(len(matches), maxCompletionsShown)], ", ")
+	if len(matches) > maxCompletionsShown {
+		view += fmt.Sprintf(" (+%d)", len(matches)-maxCompletionsShown)
+	}
+	return completionsStyle.Render("  " + view)
+}
+
+// submitPrompt acts on the value entered for the given prompt
+func (m *MainModel) submitPrompt(kind promptKind, value string) tea.Cmd {
+	if kind == filterAttributePrompt {
+		m.tableDataModel.setFilterAttribute(value)
+		if value != "" {
+			m.notice = fmt.Sprintf("Filtering on %s — press / to search", value)
+		} else {
+			m.notice = "Filtering on whole rows"
+		}
+		return nil
+	}
+
+	if kind == scanFilterPrompt {
+		cmd, err := m.tableDataModel.setScanFilter(value)
+		if err != nil {
+			m.lastErr = err
+			return nil
+		}
+		m.lastErr = nil
+		if value != "" {
+			m.notice = "Filtered scan — DynamoDB still reads and bills for the whole table"
+		}
+		return cmd
+	}
+
+	if kind == indexPrompt {
+		cmd, err := m.tableDataModel.setScanIndex(value)
+		if err != nil {
+			m.lastErr = err
+			return nil
+		}
+		m.lastErr = nil
+		if value != "" {
+			m.notice = fmt.Sprintf("Scanning index %s — rows hold %s", value, m.tableDataModel.scanIndexProjection)
+		}
+		return cmd
+	}
+
+	if kind == setAttributePrompt {
+		if value == "" {
+			m.pendingUpdate = nil
+			return nil
+		}
+		return m.setAttribute(value)
+	}
+
+	if kind == getItemPrompt {
+		return m.submitKeyValue(value)
+	}
+
+	if kind == queryPrompt {
+		pending := m.pendingGet
+		m.pendingGet = nil
+		if pending == nil {
+			return nil
+		}
+		return m.startQuery(pending.keys, value)
+	}
+
+	if kind == pathPrompt {
+		m.setRowPath(value)
+		return nil
+	}
+
+	if value == "" {
+		return nil
+	}
+
+	switch kind {
+	case sortPrompt:
+		descending := strings.HasPrefix(value, "-")
+		return m.tableDataModel.sortByAttribute(strings.TrimPrefix(value, "-"), descending)
+	case regionPrompt:
+		return m.switchRegions(value)
+	case importPrompt:
+		if !m.allowWrite() {
+			return nil
+		}
+		m.tableDataModel.loading = true
+		return tea.Batch(m.tableDataModel.loadImportFile(m.tableDataModel.selectedTable, value), m.tableDataModel.loadingIndicator.Tick)
+	}
+
+	return nil
+}
+
+// listItemsToRows extracts the raw row strings from list items
+func listItemsToRows(items []list.Item) []string {
+	rows := make([]string, len(items))
+	for i, item := range items {
+		rows[i] = item.FilterValue()
+	}
+	return rows
+}
@@ -0,0 +1,89 @@
+package lazydynamo
+
+import (
+	"log"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+)
+
+// promptHistorySize is how many entries each prompt's history keeps
+const promptHistorySize = 100
+
+// promptHistoryNames names the prompts whose entries are kept in a history,
+// by the name the history is saved under
+var promptHistoryNames = map[promptKind]string{
+	sortPrompt:            "sort",
+	regionPrompt:          "region",
+	importPrompt:          "import",
+	filterAttributePrompt: "filterAttribute",
+	pathPrompt:            "path",
+	scanFilterPrompt:      "scanFilter",
+	queryPrompt:           "query",
+}
+
+// promptHistory holds what was entered in the prompts, browsed with up and
+// down like a shell's history
+type promptHistory struct {
+	histories map[string]*tools.History
+	persist   bool   // save the histories to PromptHistoryFilePath
+	browsing  int    // how far back the prompt shows an entry, -1 for what was typed
+	draft     string // what was typed before browsing the history
+}
+
+// loadPromptHistory reads the saved histories, unless they are kept for the
+// session only
+func loadPromptHistory(persist bool) promptHistory {
+	history := promptHistory{histories: make(map[string]*tools.History), persist: persist, browsing: -1}
+
+	saved := map[string][]string{}
+	if persist {
+		var err error
+		if saved, err = tools.LoadHistories(PromptHistoryFilePath); err != nil {
+			log.Printf("Ignoring unreadable prompt history: %v", err)
+		}
+	}
+	for _, name := range promptHistoryNames {
+		history.histories[name] = tools.NewHistory(promptHistorySize, saved[name])
+	}
+	return history
+}
+
+// recordPrompt adds a value submitted in a prompt to its history
+func (m *MainModel) recordPrompt(kind promptKind, value string) {
+	name, ok := promptHistoryNames[kind]
+	if !ok || !m.history.histories[name].Add(value) || !m.history.persist {
+		return
+	}
+	if err := tools.SaveHistories(m.history.histories, PromptHistoryFilePath); err != nil {
+		log.Printf("Failed to save prompt history: %v", err)
+	}
+}
+
+// browseHistory shows an older (delta 1) or newer (delta -1) entry of the
+// open prompt's history. Going past the latest entry brings back what was
+// typed. It reports whether the prompt has a history.
+func (m *MainModel) browseHistory(delta int) bool {
+	name, ok := promptHistoryNames[m.promptKind]
+	if !ok {
+		return false
+	}
+
+	history := m.history.histories[name]
+	next := m.history.browsing + delta
+	if next < -1 || next >= history.Len() {
+		return true
+	}
+	if m.history.browsing == -1 {
+		m.history.draft = m.prompt.Value()
+	}
+
+	m.history.browsing = next
+	if next == -1 {
+		m.prompt.SetValue(m.history.draft)
+	} else {
+		m.prompt.SetValue(history.Recent(next))
+	}
+	m.prompt.CursorEnd()
+	m.updateCompletions()
+	return true
+}
@@ -0,0 +1,191 @@
+package lazydynamo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tableQuery is a query of one partition, read a page at a time
+type tableQuery struct {
+	attribute string               // partition key attribute
+	value     types.AttributeValue // partition key value
+	described string               // the partition as entered, e.g. "id=42"
+	index     string               // index queried instead of the table, if any
+	reference string               // the reference followed to the partition, e.g. "orders.userId", if any
+	lastKey   map[string]types.AttributeValue
+	pages     int // pages read so far
+}
+
+// more reports whether the partition has items past the pages read
+func (q *tableQuery) more() bool {
+	return q.lastKey != nil
+}
+
+// QueryPageMsg carries a page of query results. next pages are appended to
+// the rows already shown.
+type QueryPageMsg struct {
+	region  string
+	table   string
+	items   []list.Item
+	lastKey map[string]types.AttributeValue
+	next    bool
+	err     error
+}
+
+// startQuery queries the partition whose key value was entered and shows its
+// first page instead of the scanned rows. An empty value ends the query and
+// scans the table again.
+func (m *MainModel) startQuery(keys []keyAttribute, value string) tea.Cmd {
+	if value == "" {
+		if m.tableDataModel.query == nil {
+			return nil
+		}
+		m.tableDataModel.query = nil
+		m.notice = "Query ended — scanning the table"
+		return m.tableDataModel.restartLoad()
+	}
+
+	key, err := itemKey(keys[:1], []string{value})
+	if err != nil {
+		m.lastErr = err
+		return nil
+	}
+
+	m.lastErr = nil
+	m.tableDataModel.query = &tableQuery{
+		attribute: keys[0].name,
+		value:     key[keys[0].name],
+		described: keys[0].name + "=" + value,
+	}
+	return m.tableDataModel.restartLoad()
+}
+
+// restartLoad empties the rows and loads them again, from the query when
+// there is one and from a scan otherwise
+func (m *TableDataModel) restartLoad() tea.Cmd {
+	m.loading = true
+	m.dataLoaded = false
+	if m.query != nil {
+		m.stopScan()
+		m.query.lastKey = nil
+		m.query.pages = 0
+		return tea.Batch(m.dataList.SetItems(nil), m.queryPage(m.selectedTable, false), m.loadingIndicator.Tick)
+	}
+	return tea.Batch(m.dataList.SetItems(nil), m.startFetch(m.selectedTable, false), m.loadingIndicator.Tick)
+}
+
+// nextQueryPage reads the page after the last one shown and appends it
+func (m *TableDataModel) nextQueryPage() tea.Cmd {
+	if m.query == nil || !m.query.more() {
+		return nil
+	}
+	m.loading = true
+	return tea.Batch(m.queryPage(m.selectedTable, true), m.loadingIndicator.Tick)
+}
+
+// queryPage reads one page of the query, continuing after the last page read
+// when next is set
+func (m TableDataModel) queryPage(tableName string, next bool) tea.Cmd {
+	query := *m.query
+	return trackWork(func() tea.Msg {
+		ctx, cancel := context.WithTimeout(appContext, m.scanTimeout)
+		defer cancel()
+
+		// Binary keys are kept whole in the rows, so the items can be
+		// written to. Like a scan, a query runs without the key schema
+		// when the table may not be described.
+		keys := map[string]bool{query.attribute: true}
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil && !isAccessDenied(err) {
+			log.Printf("Failed to describe table: %v", err)
+			return QueryPageMsg{region: m.region, table: tableName, next: next, err: err}
+		}
+		if err == nil {
+			for name := range tools.KeyAttributes(tableInfo) {
+				keys[name] = true
+			}
+		}
+
+		input := &dynamodb.QueryInput{
+			TableName:                 &tableName,
+			KeyConditionExpression:    aws.String("#pk = :pk"),
+			ExpressionAttributeNames:  map[string]string{"#pk": query.attribute},
+			ExpressionAttributeValues: map[string]types.AttributeValue{":pk": query.value},
+		}
+		if query.index != "" {
+			input.IndexName = aws.String(query.index)
+		}
+		if next {
+			input.ExclusiveStartKey = query.lastKey
+		}
+		if m.scanPageLimit != 0 {
+			input.Limit = aws.Int32(int32(m.scanPageLimit))
+		}
+
+		output, err := m.client.Query(ctx, input)
+		if err != nil {
+			log.Printf("Failed to query %s: %v", tableName, err)
+			return QueryPageMsg{region: m.region, table: tableName, next: next, err: err}
+		}
+
+		var items []list.Item
+		for _, item := range output.Items {
+			mapItem, err := tools.DynamoItemToRowMap(item, keys)
+			if err != nil {
+				log.Printf("Error converting item: %v", err)
+				continue
+			}
+			data, err := json.Marshal(mapItem)
+			if err != nil {
+				log.Printf("Error marshaling item to JSON: %v", err)
+				continue
+			}
+			items = append(items, tableDataRow(string(data)))
+		}
+
+		return QueryPageMsg{region: m.region, table: tableName, items: items, lastKey: output.LastEvaluatedKey, next: next}
+	})
+}
+
+// handleQueryPage shows a page of query results
+func (m *MainModel) handleQueryPage(msg QueryPageMsg) tea.Cmd {
+	m.tableDataModel.loading = false
+	if msg.err != nil {
+		m.lastErr = fetchError(msg.err, m.profile)
+		return nil
+	}
+
+	m.tableDataModel.updateStreamKey()
+	query := m.tableDataModel.query
+	query.lastKey = msg.lastKey
+	query.pages++
+
+	items := msg.items
+	if msg.next {
+		items = append(append([]list.Item{}, m.tableDataModel.dataList.Items()...), msg.items...)
+	}
+	m.tableDataModel.dataLoaded = true
+	m.tableDataModel.sampled = false
+	m.tableDataModel.partial = ""
+	m.lastErr = nil
+	m.state = ViewingData
+
+	m.notice = fmt.Sprintf("Query %s — page %d, %s items", query.described, query.pages, formatCount(len(items)))
+	if query.more() {
+		m.notice += " — n loads the next page"
+	}
+	cmd := m.tableDataModel.setRows(items)
+	if query.reference != "" && query.pages == 1 {
+		m.showReferenced(items)
+	}
+	return cmd
+}
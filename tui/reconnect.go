@@ -0,0 +1,117 @@
+package lazydynamo
+
+import (
+	"context"
+	"os"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ReconnectedMsg carries an AWS config loaded from scratch
+type ReconnectedMsg struct {
+	config aws.Config
+	err    error
+}
+
+// loadAWSConfig loads the AWS config with custom retry settings, assuming the
+// configured role if there is one. The region follows the SDK's precedence
+// (AWS_REGION, AWS_DEFAULT_REGION, the profile) unless regions were set with
+// a flag or in the config file.
+func loadAWSConfig(appConfig tools.Config) (aws.Config, error) {
+	options := []func(*config.LoadOptions) error{
+		config.WithRetryer(func() aws.Retryer {
+			return retry.AddWithMaxAttempts(retry.NewStandard(), 20)
+		}),
+	}
+	if len(appConfig.Regions) > 0 {
+		options = append(options, config.WithRegion(appConfig.Regions[0]))
+	}
+	if appConfig.Profile != "" {
+		options = append(options, config.WithSharedConfigProfile(appConfig.Profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.TODO(), options...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	if cfg.Region == "" {
+		cfg.Region = DefaultRegion
+	}
+
+	// Assume the configured role; the credentials cache refreshes the
+	// temporary credentials before they expire
+	if appConfig.RoleArn != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), appConfig.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+			if appConfig.ExternalID != "" {
+				o.ExternalID = aws.String(appConfig.ExternalID)
+			}
+			if appConfig.RoleSessionName != "" {
+				o.RoleSessionName = appConfig.RoleSessionName
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	return cfg, nil
+}
+
+// dynamoEndpoint returns the endpoint DynamoDB requests go to instead of
+// AWS, e.g. DynamoDB Local, from AWS_ENDPOINT_URL_DYNAMODB or the endpoint
+// of all services, AWS_ENDPOINT_URL. Empty means the regional AWS endpoint.
+func dynamoEndpoint() string {
+	if endpoint := os.Getenv("AWS_ENDPOINT_URL_DYNAMODB"); endpoint != "" {
+		return endpoint
+	}
+	return os.Getenv("AWS_ENDPOINT_URL")
+}
+
+// newDynamoClient creates a DynamoDB client for region. The endpoint override
+// only applies to DynamoDB, so STS calls still reach AWS.
+func newDynamoClient(cfg aws.Config, region string) *dynamodb.Client {
+	endpoint := dynamoEndpoint()
+	return dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		o.Region = region
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+}
+
+// reconnect reloads the AWS config, re-reading credentials and the SSO cache
+func reconnect(appConfig tools.Config) tea.Cmd {
+	return func() tea.Msg {
+		cfg, err := loadAWSConfig(appConfig)
+		return ReconnectedMsg{config: cfg, err: err}
+	}
+}
+
+// handleReconnected swaps in fresh clients and retries loading the
+// collections and, if one is selected, the table
+func (m *MainModel) handleReconnected(msg ReconnectedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.loading = false
+		m.lastErr = reconnectableError{msg.err}
+		return nil
+	}
+
+	m.awsConfig = msg.config
+	m.accountID = ""
+	m.buildClients()
+	m.tableDataModel.client = m.clientFor(m.tableDataModel.region)
+	m.lastErr = nil
+	m.notice = "Reconnected"
+
+	cmds := []tea.Cmd{m.refreshCollections()}
+	if m.tableDataModel.selectedTable != "" {
+		m.tableDataModel.loading = true
+		cmds = append(cmds, m.tableDataModel.startRefresh(m.tableDataModel.selectedTable), m.tableDataModel.loadingIndicator.Tick)
+	}
+	return tea.Batch(cmds...)
+}
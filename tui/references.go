@@ -0,0 +1,146 @@
+package lazydynamo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ReferenceResolvedMsg carries the partition key a followed reference is
+// looked up by in its target table or index
+type ReferenceResolvedMsg struct {
+	region    string
+	reference tools.Reference
+	from      string // the attribute followed, e.g. "orders.userId"
+	value     string
+	key       keyAttribute
+	err       error
+}
+
+// followReference looks up the item the selected attribute of the viewed row
+// refers to, as configured in the references of the config file
+func (m *MainModel) followReference() tea.Cmd {
+	attribute, ok := m.viewRowModel.selectedAttribute()
+	if !ok {
+		m.notice = "Press tab to select an attribute first"
+		return nil
+	}
+
+	table := m.tableDataModel.selectedTable
+	reference, ok := tools.MatchReference(m.appConfig.References, table, attribute.name)
+	if !ok {
+		m.notice = fmt.Sprintf("%s doesn't refer to a table — add it to the references in %s", attribute.name, ConfigFilePath)
+		return nil
+	}
+
+	m.tableDataModel.lookingUp = true
+	from := table + "." + attribute.name
+	return tea.Batch(m.tableDataModel.resolveReference(reference, from, attribute.value), m.tableDataModel.loadingIndicator.Tick)
+}
+
+// resolveReference describes the target table of a reference to learn the
+// partition key its values are looked up by
+func (m TableDataModel) resolveReference(reference tools.Reference, from, value string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		msg := ReferenceResolvedMsg{region: m.region, reference: reference, from: from, value: value}
+		tableInfo, err := m.describeTable(ctx, reference.Target)
+		if err != nil {
+			log.Printf("Failed to describe table: %v", err)
+			msg.err = err
+			return msg
+		}
+
+		keySchema := tableInfo.KeySchema
+		if reference.Index != "" {
+			keySchema = nil
+			for _, index := range tableInfo.GlobalSecondaryIndexes {
+				if aws.ToString(index.IndexName) == reference.Index {
+					keySchema = index.KeySchema
+				}
+			}
+			if keySchema == nil {
+				msg.err = fmt.Errorf("%s has no global secondary index %s", reference.Target, reference.Index)
+				return msg
+			}
+		}
+
+		partitionKey, _, err := extractPrimaryKeyAttributes(keySchema)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.key = keyAttribute{name: partitionKey, attrType: types.ScalarAttributeTypeS}
+		for _, definition := range tableInfo.AttributeDefinitions {
+			if aws.ToString(definition.AttributeName) == partitionKey {
+				msg.key.attrType = definition.AttributeType
+			}
+		}
+		return msg
+	}
+}
+
+// openReference switches to the target table of a resolved reference and
+// queries it for the referenced partition
+func (m *MainModel) openReference(msg ReferenceResolvedMsg) tea.Cmd {
+	m.tableDataModel.lookingUp = false
+	if msg.err != nil {
+		m.lastErr = friendlyAWSError(msg.err, m.profile)
+		return nil
+	}
+
+	key, err := itemKey([]keyAttribute{msg.key}, []string{msg.value})
+	if err != nil {
+		m.lastErr = fmt.Errorf("%s can't be looked up in %s: %w", msg.from, msg.reference.Target, err)
+		return nil
+	}
+
+	target := msg.reference.Target
+	item := m.collectionItem(msg.region, target)
+	for index, listed := range m.collectionsList.Items() {
+		if listed == item {
+			m.collectionsList.Select(index)
+		}
+	}
+	m.tableDataModel.selectTable(m.clientFor(msg.region), msg.region, target)
+
+	described := msg.key.name + "=" + msg.value
+	if msg.reference.Index != "" {
+		described = msg.reference.Index + " " + described
+	}
+	m.lastErr = nil
+	m.tableDataModel.query = &tableQuery{
+		attribute: msg.key.name,
+		value:     key[msg.key.name],
+		described: described,
+		index:     msg.reference.Index,
+		reference: msg.from,
+	}
+	return m.tableDataModel.restartLoad()
+}
+
+// showReferenced opens the item a reference led to when it is the only one,
+// and says where the listed items came from otherwise
+func (m *MainModel) showReferenced(items []list.Item) {
+	query := m.tableDataModel.query
+	switch len(items) {
+	case 0:
+		m.notice = fmt.Sprintf("%s refers to no item: nothing with %s in %s", query.reference, query.described, m.tableDataModel.selectedTable)
+	case 1:
+		if row, ok := items[0].(tableDataRow); ok {
+			m.openRow(string(row))
+			m.notice = fmt.Sprintf("Followed %s to %s", query.reference, m.tableDataModel.selectedTable)
+		}
+	default:
+		m.notice = fmt.Sprintf("Followed %s to %s items with %s in %s", query.reference, formatCount(len(items)), query.described, m.tableDataModel.selectedTable)
+	}
+}
@@ -0,0 +1,30 @@
+package lazydynamo
+
+import (
+	"log"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// regionPaneHeight is the height of the AWS region pane, borders included
+const regionPaneHeight = 5
+
+// toggleRegionPane hides or shows the AWS region pane, giving its height to
+// the collections list, and saves the choice to the config file. The status
+// bar still shows the region.
+func (m *MainModel) toggleRegionPane() tea.Cmd {
+	m.hideRegionPane = !m.hideRegionPane
+	hide := m.hideRegionPane
+
+	m.notice = "Region pane shown"
+	if hide {
+		m.notice = "Region pane hidden — ctrl+g shows it"
+	}
+	if err := tools.UpdateConfig(ConfigFilePath, func(c *tools.Config) { c.HideRegionPane = hide }); err != nil {
+		log.Printf("Failed to save the region pane setting: %v", err)
+		m.notice += " (not saved)"
+	}
+
+	return m.relayout()
+}
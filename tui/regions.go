@@ -0,0 +1,109 @@
+package lazydynamo
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// regionSeparator joins the region and table name of collections listed in
+// multi-region mode. Table names can't contain it.
+const regionSeparator = "/"
+
+// multiRegion reports whether the collections of several regions are shown
+func (m MainModel) multiRegion() bool {
+	return len(m.regions) > 1
+}
+
+// activeRegions returns the regions whose collections are listed
+func (m MainModel) activeRegions() []string {
+	if m.multiRegion() {
+		return m.regions
+	}
+	return []string{m.region}
+}
+
+// regionKey identifies the active regions, used to drop collections that
+// arrive after the user switched regions
+func (m MainModel) regionKey() string {
+	return strings.Join(m.activeRegions(), ",")
+}
+
+// clientFor returns the DynamoDB client of region
+func (m MainModel) clientFor(region string) *dynamodb.Client {
+	if client, ok := m.clients[region]; ok {
+		return client
+	}
+	return m.client
+}
+
+// collectionItem builds the collections list entry of a table, prefixed with
+// its region in multi-region mode
+func (m MainModel) collectionItem(region, table string) list.Item {
+	if m.multiRegion() {
+		return tableNameItem(region + regionSeparator + table)
+	}
+	return tableNameItem(table)
+}
+
+// splitCollectionItem returns the region and table name of a collections list
+// entry
+func (m MainModel) splitCollectionItem(item tableNameItem) (region, table string) {
+	if m.multiRegion() {
+		if region, table, ok := strings.Cut(string(item), regionSeparator); ok {
+			return region, table
+		}
+	}
+	return m.region, string(item)
+}
+
+// setRegions points the model at one or more regions. The first region is the
+// primary one; with several, their collections are listed together.
+func (m *MainModel) setRegions(regions []string) {
+	m.region = regions[0]
+	m.regions = nil
+	if len(regions) > 1 {
+		m.regions = regions
+	}
+
+	m.buildClients()
+
+	// Data from the previous regions no longer applies
+	m.tableDataModel.selectTable(m.client, m.region, "")
+	m.tableDataModel.dataLoaded = false
+	m.collectionsReady = false
+}
+
+// buildClients creates a client for every active region from the current AWS
+// config. It builds a fresh map, so fetches still running with the previous
+// clients never read it while it is being filled.
+func (m *MainModel) buildClients() {
+	regions := m.activeRegions()
+	m.clients = make(map[string]*dynamodb.Client, len(regions))
+	for _, region := range regions {
+		m.clients[region] = newDynamoClient(m.awsConfig, region)
+	}
+	m.client = m.clients[m.region]
+}
+
+// switchRegions moves to the given regions and reloads their collections.
+// regions is a comma separated list such as "us-east-1,eu-west-1".
+func (m *MainModel) switchRegions(value string) tea.Cmd {
+	var regions []string
+	for _, region := range strings.Split(value, ",") {
+		if region = strings.ToLower(strings.TrimSpace(region)); region != "" {
+			regions = append(regions, region)
+		}
+	}
+	if len(regions) == 0 {
+		return nil
+	}
+
+	m.setRegions(regions)
+	m.tableDataModel.stopLive()
+	m.state = ViewingCollections
+
+	return tea.Batch(m.collectionsList.SetItems(nil), m.tableDataModel.dataList.SetItems(nil), m.startCollectionsFetch())
+}
@@ -0,0 +1,41 @@
+package lazydynamo
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startRescan rescans the table from DynamoDB like a refresh, but leaves the
+// loaded rows on screen until the new ones arrive. A rescan that fails or is
+// cut short keeps them altogether.
+func (m *TableDataModel) startRescan(tableName string) tea.Cmd {
+	m.tableInfo.invalidate(tableName)
+
+	fetch := m.startBackgroundRefresh(tableName)
+	return func() tea.Msg {
+		msg := fetch()
+		if fetched, ok := msg.(DataFetchedMsg); ok {
+			fetched.rescan = true
+			return fetched
+		}
+		return msg
+	}
+}
+
+// swapRows replaces the rows shown with those of a background refresh,
+// keeping the selection where it was. The list keeps its filter by itself.
+func (m *TableDataModel) swapRows(items []list.Item) tea.Cmd {
+	index, gridCursor := m.dataList.Index(), m.grid.Cursor()
+
+	cmd := m.setRows(items)
+
+	if !m.dataList.IsFiltered() {
+		// The filtered rows only come in later, so the cursor stays put
+		index = min(index, max(len(m.dataList.Items())-1, 0))
+	}
+	m.dataList.Select(index)
+	if m.showGrid {
+		m.grid.SetCursor(min(gridCursor, max(len(m.grid.Rows())-1, 0)))
+	}
+	return cmd
+}
@@ -0,0 +1,188 @@
+package lazydynamo
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var treeCursorStyle = lipgloss.NewStyle().Reverse(true)
+
+// treeNode is one value of a row in the tree view. Maps and lists are
+// containers whose children can be folded away.
+type treeNode struct {
+	label     string // attribute name or list index
+	value     string // JSON of a scalar value
+	container byte   // '{' or '[' for maps and lists, 0 for scalars
+	children  []*treeNode
+	collapsed bool
+	depth     int
+	parent    *treeNode
+}
+
+// rowTree is a row parsed for the tree view, with a cursor on one of the
+// visible nodes
+type rowTree struct {
+	nodes  []*treeNode // top-level values
+	cursor int
+}
+
+// newRowTree parses a row, or the part of it a path selected, into a tree
+// with every container expanded
+func newRowTree(rawJSON string) (*rowTree, error) {
+	decoder := json.NewDecoder(strings.NewReader(rawJSON))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	root := buildTreeNode("", value, -1, nil)
+	if root.container == 0 {
+		return &rowTree{nodes: []*treeNode{root}}, nil
+	}
+	return &rowTree{nodes: root.children}, nil
+}
+
+func buildTreeNode(label string, value interface{}, depth int, parent *treeNode) *treeNode {
+	node := &treeNode{label: label, depth: depth, parent: parent}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		node.container = '{'
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			node.children = append(node.children, buildTreeNode(key, v[key], depth+1, node))
+		}
+	case []interface{}:
+		node.container = '['
+		for i, element := range v {
+			node.children = append(node.children, buildTreeNode(fmt.Sprintf("[%d]", i), element, depth+1, node))
+		}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			data = []byte(fmt.Sprint(v))
+		}
+		node.value = string(data)
+	}
+
+	// The root isn't shown, so its children start at depth 0
+	if depth < 0 {
+		for _, child := range node.children {
+			child.parent = nil
+		}
+	}
+	return node
+}
+
+// visible returns the nodes not hidden in a collapsed container, in order
+func (t *rowTree) visible() []*treeNode {
+	var nodes []*treeNode
+	var walk func([]*treeNode)
+	walk = func(children []*treeNode) {
+		for _, node := range children {
+			nodes = append(nodes, node)
+			if node.container != 0 && !node.collapsed {
+				walk(node.children)
+			}
+		}
+	}
+	walk(t.nodes)
+	return nodes
+}
+
+// move moves the cursor by delta visible nodes
+func (t *rowTree) move(delta int) {
+	t.cursor = max(0, min(t.cursor+delta, len(t.visible())-1))
+}
+
+// selected returns the node under the cursor
+func (t *rowTree) selected() (*treeNode, bool) {
+	nodes := t.visible()
+	if t.cursor < 0 || t.cursor >= len(nodes) {
+		return nil, false
+	}
+	return nodes[t.cursor], true
+}
+
+// expand unfolds the container under the cursor
+func (t *rowTree) expand() {
+	if node, ok := t.selected(); ok && node.container != 0 {
+		node.collapsed = false
+	}
+}
+
+// collapse folds the container under the cursor, or when it is already
+// folded or a scalar, moves the cursor to its parent
+func (t *rowTree) collapse() {
+	node, ok := t.selected()
+	if !ok {
+		return
+	}
+	if node.container != 0 && !node.collapsed {
+		node.collapsed = true
+		return
+	}
+	if node.parent != nil {
+		t.selectNode(node.parent)
+	}
+}
+
+// toggle folds or unfolds the container under the cursor
+func (t *rowTree) toggle() {
+	if node, ok := t.selected(); ok && node.container != 0 {
+		node.collapsed = !node.collapsed
+	}
+}
+
+func (t *rowTree) selectNode(target *treeNode) {
+	for i, node := range t.visible() {
+		if node == target {
+			t.cursor = i
+			return
+		}
+	}
+}
+
+// render draws the visible nodes, each cut to width, and returns the line
+// the cursor is on so the viewport can keep it in view
+func (t *rowTree) render(width int) (string, int) {
+	nodes := t.visible()
+	lines := make([]string, len(nodes))
+	for i, node := range nodes {
+		line := strings.Repeat("  ", node.depth) + node.line()
+		line, _ = truncateToWidth(line, max(width, 1))
+		if i == t.cursor {
+			line = treeCursorStyle.Render(line)
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n"), t.cursor
+}
+
+// line renders a node without its indentation, e.g. `▸ address {4}`
+func (n *treeNode) line() string {
+	label := n.label
+	if n.container == 0 {
+		if label == "" {
+			return "  " + n.value
+		}
+		return "  " + label + ": " + n.value
+	}
+
+	marker := "▾ "
+	if n.collapsed {
+		marker = "▸ "
+	}
+	closing := map[byte]string{'{': "}", '[': "]"}[n.container]
+	return fmt.Sprintf("%s%s %c%d%s", marker, label, n.container, len(n.children), closing)
+}
@@ -0,0 +1,138 @@
+package lazydynamo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/components"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	heavyScanConfirmID = "heavy-scan"
+
+	// provisionedScanShare is the share of a provisioned table's read
+	// capacity a scan may use, leaving the rest to the table's applications
+	provisionedScanShare = 0.5
+	// rcuPerSegment is the least provisioned read capacity each parallel
+	// segment of a scan gets, so low-capacity tables are read by few segments
+	rcuPerSegment = 50
+	// heavyScanDuration is how long a scan of a provisioned table may be
+	// expected to take before it is confirmed first
+	heavyScanDuration = time.Minute
+	// readUnitBytes is the size an eventually consistent read unit covers
+	// twice: a scan consumes half a unit per 4 KB
+	readUnitBytes = 4096
+)
+
+// HeavyScanMsg is sent instead of scanning a provisioned table whose scan
+// would take long at the read capacity it may use
+type HeavyScanMsg struct {
+	region   string
+	table    string
+	estimate float64 // read capacity units the whole scan consumes
+	rate     float64 // read capacity units per second the scan may use
+	resuming bool    // the scan continues an interrupted one
+}
+
+// readCapacity returns the provisioned read capacity of the table, or of the
+// global secondary index that is scanned. It reports false for on-demand
+// tables and when the capacity isn't known.
+func readCapacity(tableInfo *types.TableDescription, index string) (float64, bool) {
+	if tableInfo.BillingModeSummary != nil && tableInfo.BillingModeSummary.BillingMode == types.BillingModePayPerRequest {
+		return 0, false
+	}
+
+	throughput := tableInfo.ProvisionedThroughput
+	for _, gsi := range tableInfo.GlobalSecondaryIndexes {
+		if index != "" && aws.ToString(gsi.IndexName) == index {
+			throughput = gsi.ProvisionedThroughput
+		}
+	}
+	if throughput == nil || aws.ToInt64(throughput.ReadCapacityUnits) <= 0 {
+		return 0, false
+	}
+	return float64(aws.ToInt64(throughput.ReadCapacityUnits)), true
+}
+
+// estimatedScanRCU estimates the read capacity units a scan of sizeBytes
+// consumes with eventually consistent reads
+func estimatedScanRCU(sizeBytes int64) float64 {
+	return math.Ceil(float64(sizeBytes)/readUnitBytes) / 2
+}
+
+// provisionedSegments caps the parallel segments of a scan by the read
+// capacity of a provisioned table
+func provisionedSegments(maxSegments int, rcu float64) int {
+	return max(1, min(maxSegments, int(rcu/rcuPerSegment)))
+}
+
+// capacityLimiter paces a scan's segments so together they consume at most
+// rate read capacity units per second
+type capacityLimiter struct {
+	rate float64
+	mu   sync.Mutex
+	next time.Time // when the capacity consumed so far is paid off
+}
+
+func newCapacityLimiter(rate float64) *capacityLimiter {
+	return &capacityLimiter{rate: rate}
+}
+
+// wait holds a segment back after a page that consumed units, until the
+// scan's consumption is back under the rate. A nil limiter never waits.
+func (l *capacityLimiter) wait(ctx context.Context, units float64) error {
+	if l == nil || units <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(units / l.rate * float64(time.Second)))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// confirmHeavyScan asks before scanning a provisioned table slowly, offering
+// sample mode instead
+func (m *MainModel) confirmHeavyScan(msg HeavyScanMsg) {
+	m.pendingHeavyScan = &msg
+	minutes := math.Ceil(msg.estimate / msg.rate / 60)
+	message := fmt.Sprintf("%s is provisioned; a full scan reads ~%.0f RCU and at %.0f RCU/s takes ~%.0f min. Scan anyway? (P samples instead)", msg.table, msg.estimate, msg.rate, minutes)
+	m.confirmDialog = m.confirmDialog.Open(heavyScanConfirmID, message)
+}
+
+// startHeavyScan scans the table once its slow scan was confirmed
+func (m *MainModel) startHeavyScan(msg components.ConfirmResultMsg) tea.Cmd {
+	pending := m.pendingHeavyScan
+	m.pendingHeavyScan = nil
+	if pending == nil || !msg.Confirmed || !m.tableDataModel.isSelected(pending.region, pending.table) {
+		return nil
+	}
+
+	m.tableDataModel.confirmedHeavyScan = pending.table
+	m.tableDataModel.loading = true
+	fetch := m.tableDataModel.startFetch(pending.table, false)
+	if pending.resuming {
+		// Loading the table again picks up the interrupted scan's progress
+		fetch = m.tableDataModel.startScan(pending.table)
+	}
+	return tea.Batch(fetch, m.tableDataModel.loadingIndicator.Tick)
+}
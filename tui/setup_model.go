@@ -0,0 +1,250 @@
+package lazydynamo
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/TheChessDev/lazydynamo/internals/components"
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ErrSetupQuit is returned by RunSetup when the user quit instead of
+// finishing the setup
+var ErrSetupQuit = errors.New("setup quit")
+
+// setupStep is the choice the setup screen is asking for
+type setupStep int
+
+const (
+	pickProfile setupStep = iota
+	pickRegion
+)
+
+type SetupKeyMap struct {
+	Select key.Binding
+	Back   key.Binding
+	Quit   key.Binding
+}
+
+func (k SetupKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Select, k.Back, k.Quit}
+}
+
+func (k SetupKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Select, k.Back, k.Quit}}
+}
+
+var setupKeys = SetupKeyMap{
+	Select: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "select"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back / skip setup"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+}
+
+// SetupModel is the screen shown on first launch to pick the AWS profile and
+// region, which are then saved to the config file
+type SetupModel struct {
+	keys     SetupKeyMap
+	help     help.Model
+	step     setupStep
+	profiles []tools.AWSProfile
+	list     list.Model
+	width    int
+	height   int
+
+	profile string
+	region  string
+	done    bool // the choices were made or the setup was skipped
+	quit    bool
+}
+
+// NewSetup builds the setup screen, starting with the given profile selected
+func NewSetup(profiles []tools.AWSProfile, profile string) SetupModel {
+	l := list.New(nil, itemDelegate{}, 10, 10)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.Styles.PaginationStyle = paginationStyle
+	l.KeyMap.Quit.SetEnabled(false)
+
+	m := SetupModel{
+		keys:     setupKeys,
+		help:     help.New(),
+		profiles: profiles,
+		list:     l,
+		profile:  profile,
+	}
+	themeHelp(&m.help.Styles)
+	m.showProfiles()
+	return m
+}
+
+// FirstLaunch reports whether lazydynamo runs for the first time, which is
+// when there is no config file yet
+func FirstLaunch() bool {
+	_, err := os.Stat(ConfigFilePath)
+	return os.IsNotExist(err)
+}
+
+// RunSetup shows the setup screen and saves the chosen profile and region to
+// the config file. Choices already made with flags or the environment are
+// kept for this run. Skipping the setup saves an empty config, so it isn't
+// shown again.
+func RunSetup(config *tools.Config) error {
+	loadTheme(*config)
+
+	profiles, err := tools.ListAWSProfiles()
+	if err != nil {
+		log.Printf("Couldn't read the AWS profiles: %v", err)
+	}
+	if len(profiles) == 0 {
+		profiles = []tools.AWSProfile{{Name: "default"}}
+	}
+
+	final, err := tea.NewProgram(NewSetup(profiles, awsProfile(*config)), tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	setup := final.(SetupModel)
+	if setup.quit {
+		return ErrSetupQuit
+	}
+
+	saved := tools.Config{}
+	if setup.region != "" {
+		saved.Profile = setup.profile
+		saved.Regions = []string{setup.region}
+		if config.Profile == "" {
+			config.Profile = setup.profile
+		}
+		if len(config.Regions) == 0 {
+			config.Regions = saved.Regions
+		}
+	}
+	return tools.SaveConfig(&saved, ConfigFilePath)
+}
+
+func (m SetupModel) Init() tea.Cmd {
+	return nil
+}
+
+// showProfiles lists the profiles with the chosen one selected
+func (m *SetupModel) showProfiles() {
+	m.step = pickProfile
+	items := make([]list.Item, len(m.profiles))
+	selected := 0
+	for i, profile := range m.profiles {
+		items[i] = tableNameItem(profile.Name)
+		if profile.Name == m.profile {
+			selected = i
+		}
+	}
+	m.list.ResetFilter()
+	m.list.SetItems(items)
+	m.list.Select(selected)
+}
+
+// showRegions lists the regions with the profile's own region selected
+func (m *SetupModel) showRegions() {
+	m.step = pickRegion
+	region := DefaultRegion
+	for _, profile := range m.profiles {
+		if profile.Name == m.profile && profile.Region != "" {
+			region = profile.Region
+		}
+	}
+
+	regions := tools.AWSRegions
+	if !slices.Contains(regions, region) {
+		regions = append([]string{region}, regions...)
+	}
+	items := make([]list.Item, len(regions))
+	for i, name := range regions {
+		items[i] = tableNameItem(name)
+	}
+	m.list.ResetFilter()
+	m.list.SetItems(items)
+	m.list.Select(slices.Index(regions, region))
+}
+
+func (m SetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.help.Width = msg.Width
+		m.list.SetSize(msg.Width-4, msg.Height-8)
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Quit) {
+			m.quit = true
+			return m, tea.Quit
+		}
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Select):
+			item, ok := m.list.SelectedItem().(tableNameItem)
+			if !ok {
+				return m, nil
+			}
+			if m.step == pickProfile {
+				m.profile = string(item)
+				m.showRegions()
+				return m, nil
+			}
+			m.region = string(item)
+			m.done = true
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Back):
+			if m.list.FilterState() == list.FilterApplied {
+				break
+			}
+			if m.step == pickRegion {
+				m.showProfiles()
+				return m, nil
+			}
+			m.done = true
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m SetupModel) View() string {
+	if m.done || m.quit {
+		return ""
+	}
+
+	label := "Welcome to lazydynamo — pick the AWS profile to use"
+	if m.step == pickRegion {
+		label = fmt.Sprintf("Profile %s — pick the region whose tables to list", m.profile)
+	}
+	box := components.NewDefaultBoxWithLabel(BoxActiveColor, lipgloss.Left, lipgloss.Left)
+	note := "Saved to " + ConfigFilePath + "; change it there or with flags later."
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		box.Render(label, m.list.View(), m.width-4, m.height-6),
+		" "+note,
+		" "+m.help.View(legendFor(m.list, m.keys)),
+	)
+}
@@ -0,0 +1,42 @@
+package lazydynamo
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// appContext is cancelled once the program exits, stopping the scans and
+// queries still running
+var appContext, cancelAppContext = context.WithCancel(context.Background())
+
+// runningWork counts the scans and queries that haven't returned yet
+var runningWork atomic.Int64
+
+// trackWork runs cmd as background work that StopBackgroundWork waits for
+func trackWork(cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		runningWork.Add(1)
+		defer runningWork.Add(-1)
+		return cmd()
+	}
+}
+
+// StopBackgroundWork cancels the scans and queries still running after the
+// program exited and waits up to timeout for them to return, so nothing
+// logs to a file that is about to be closed. It reports whether they all
+// returned in time.
+func StopBackgroundWork(timeout time.Duration) bool {
+	cancelAppContext()
+
+	deadline := time.Now().Add(timeout)
+	for runningWork.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return true
+}
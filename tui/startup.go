@@ -0,0 +1,51 @@
+package lazydynamo
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Modes the app can start in
+const (
+	StartModeCollections = "collections"
+	StartModeView        = "view"
+)
+
+// ValidStartMode reports whether mode names a mode the app can start in. An
+// empty mode starts in the collections list.
+func ValidStartMode(mode string) bool {
+	return mode == "" || mode == StartModeCollections || mode == StartModeView
+}
+
+// openStartTable selects and scans the table asked for on the command line
+// once the collections are listed. Cached collections may be out of date, so
+// a table missing from them is looked for again in the fresh list.
+func (m *MainModel) openStartTable(fromCache bool) tea.Cmd {
+	if m.startTable == "" {
+		return nil
+	}
+
+	for index, item := range m.collectionsList.Items() {
+		i, ok := item.(tableNameItem)
+		if !ok {
+			continue
+		}
+		region, table := m.splitCollectionItem(i)
+		if string(i) != m.startTable && table != m.startTable {
+			continue
+		}
+
+		m.startTable = ""
+		m.collectionsList.Select(index)
+		m.tableDataModel.loading = true
+		m.tableDataModel.selectTable(m.clientFor(region), region, table)
+		return tea.Batch(m.tableDataModel.startScan(table), m.tableDataModel.loadingIndicator.Tick)
+	}
+
+	if !fromCache {
+		m.lastErr = fmt.Errorf("table %q not found in %s", m.startTable, m.regionKey())
+		m.startTable = ""
+	}
+	return nil
+}
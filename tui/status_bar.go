@@ -0,0 +1,103 @@
+package lazydynamo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	statusBarStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Background(lipgloss.Color("236"))
+	statusSegmentStyle = statusBarStyle.Padding(0, 1)
+	statusModeStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("10")).Bold(true).Padding(0, 1)
+	readOnlyBadgeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("1")).Bold(true).Padding(0, 1)
+	liveBadgeStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("11")).Bold(true).Padding(0, 1)
+	errorBannerStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("1")).Padding(0, 1)
+)
+
+// renderStatusBar renders the bottom bar summarizing where the user is:
+// region, profile, table, number of loaded items and the current mode
+func (m MainModel) renderStatusBar(width int) string {
+	table := "no table"
+	if m.tableDataModel.selectedTable != "" {
+		table = m.tableDataModel.selectedTable
+	}
+
+	segments := []string{
+		statusModeStyle.Render(m.GetCurrentState()),
+		statusSegmentStyle.Render(m.region),
+		statusSegmentStyle.Render(m.profile),
+		statusSegmentStyle.Render(table),
+	}
+
+	if m.endpoint != "" {
+		segments = append(segments, statusSegmentStyle.Render("endpoint: "+m.endpoint))
+	}
+
+	if m.readOnly {
+		segments = append(segments, readOnlyBadgeStyle.Render("READ-ONLY"))
+	}
+
+	if m.roleArn != "" {
+		segments = append(segments, statusSegmentStyle.Render("role: "+roleName(m.roleArn)))
+	}
+
+	if m.tableDataModel.liveInterval != 0 {
+		segments = append(segments, liveBadgeStyle.Render(fmt.Sprintf("LIVE (%s)", m.tableDataModel.liveInterval)))
+	}
+
+	if m.tableDataModel.scanIndex != "" {
+		segments = append(segments, statusSegmentStyle.Render("index: "+m.tableDataModel.scanIndex))
+	}
+
+	if m.tableDataModel.scanFilterInput != "" {
+		segments = append(segments, statusSegmentStyle.Render("scan filter: "+m.tableDataModel.scanFilterInput))
+	}
+
+	if m.tableDataModel.sortAttribute != "" {
+		direction := "↑"
+		if m.tableDataModel.sortDescending {
+			direction = "↓"
+		}
+		segments = append(segments, statusSegmentStyle.Render(fmt.Sprintf("sort: %s %s", m.tableDataModel.sortAttribute, direction)))
+	}
+
+	if m.tableDataModel.filterAttribute != "" {
+		segments = append(segments, statusSegmentStyle.Render("filter: "+m.tableDataModel.filterAttribute))
+	}
+
+	if m.tableDataModel.dataLoaded {
+		segments = append(segments, statusSegmentStyle.Render(fmt.Sprintf("%d items", len(m.tableDataModel.dataList.Items()))))
+	}
+
+	if m.notice != "" {
+		segments = append(segments, statusSegmentStyle.Render(m.notice))
+	}
+
+	bar := lipgloss.JoinHorizontal(lipgloss.Top, segments...)
+
+	return statusBarStyle.Width(width).Render(bar)
+}
+
+// renderErrorBanner renders the last fetch error across the full width
+func (m MainModel) renderErrorBanner(width int) string {
+	message := "Error: " + m.lastErr.Error()
+
+	var reconnectable reconnectableError
+	if errors.As(m.lastErr, &reconnectable) && !strings.Contains(message, "ctrl+k") {
+		message += " (ctrl+k to reconnect)"
+	}
+
+	return errorBannerStyle.Width(width).Render(message)
+}
+
+// roleName extracts the role name from a role ARN such as
+// arn:aws:iam::123456789012:role/Admin
+func roleName(roleArn string) string {
+	if i := strings.LastIndex(roleArn, "/"); i >= 0 {
+		return roleArn[i+1:]
+	}
+	return roleArn
+}
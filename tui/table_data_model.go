@@ -3,10 +3,13 @@ package lazydynamo
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,18 +22,89 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
-type DataFetchedMsg []list.Item
+// DataFetchedMsg carries the rows scanned from a table. The table name is used
+// to drop results that arrive after the user selected a different table.
+type DataFetchedMsg struct {
+	region string
+	table  string
+	items  []list.Item
+
+	fromCache  bool // items were loaded from the cache and a refresh should follow
+	background bool // items come from a background refresh of cached data
+	rescan     bool // the background refresh was asked for, so it is reported
+
+	consumedCapacity float64 // read capacity units the scan consumed
+	sampled          bool    // the scan stopped once it had sampleSize items
+	warning          string  // shown instead of the scan summary, if set
+
+	paced   float64 // read capacity units per second a provisioned table's scan was held to, if any
+	partial string  // why the scan stopped before reading the whole table, if it did
+	stopped bool    // the scan was stopped with esc
+	err     error   // error that cut the scan short, if any
+
+	resumed   int  // rows carried over from an interrupted scan this one resumed
+	resumable bool // the partial scan's progress was cached, so loading the table again resumes it
+}
+
+// errScanStopped is the cause of a scan cancelled with esc, as opposed to
+// one replaced by a newer scan. The rows it read so far are still shown.
+var errScanStopped = errors.New("scan stopped")
+
+// ScanCancelledMsg is sent when a scan stopped because it was cancelled. It
+// carries no data so a cancelled scan never replaces what is on screen.
+type ScanCancelledMsg struct{}
 
 type tableDataRow string
 
 func (i tableDataRow) FilterValue() string { return string(i) }
 
-type tableDataDelegate struct{}
+// horizontalScrollStep is the number of characters the data rows shift per
+// horizontal scroll key press.
+const horizontalScrollStep = 10
+
+// maxGridColumnWidth caps how wide a single column in the grid view can grow
+const maxGridColumnWidth = 30
+
+// Bounds of the number of items each scan request asks for
+const (
+	MinScanPageLimit = 1
+	MaxScanPageLimit = 1000
+)
+
+// DefaultSampleSize is how many items a scan in sample mode stops after,
+// unless configured
+const DefaultSampleSize = 500
+
+// DefaultFlattenDepth is how many levels of nested attributes the flattened
+// grid expands, unless configured
+const DefaultFlattenDepth = 3
+
+// defaultScanPageLimit is used when the table's item size isn't known
+const defaultScanPageLimit = 100
+
+// scanPageBytes is the most data DynamoDB returns for one scan request
+const scanPageBytes = 1024 * 1024
+
+// segmentBytes is roughly how much of a table each parallel scan segment
+// should read
+const segmentBytes = 8 * scanPageBytes
+
+// defaultScanTimeout limits a table scan unless the user configured otherwise
+const defaultScanTimeout = 120 * time.Second
 
-func (d tableDataDelegate) Height() int                             { return 1 }
+type tableDataDelegate struct {
+	offset int             // horizontal scroll offset, in runes
+	marked map[string]bool // rows marked for bulk actions
+	lines  int             // lines each row is wrapped over, 0 to truncate
+}
+
+func (d tableDataDelegate) Height() int                             { return max(d.lines, 1) }
 func (d tableDataDelegate) Spacing() int                            { return 0 }
 func (d tableDataDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 func (d tableDataDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
@@ -41,32 +115,79 @@ func (d tableDataDelegate) Render(w io.Writer, m list.Model, index int, listItem
 
 	str := fmt.Sprintf("%s", i)
 
+	// Shift the row sideways by the horizontal scroll offset
+	runes := []rune(str)
+	offset := min(d.offset, len(runes))
+	str = string(runes[offset:])
+
 	modelWidth := m.Width()
 	maxWidth := modelWidth - 3 // Adjust for padding or any prefix/suffix
 
-	// Trim the JSON string if it exceeds the model width
-	if len(str) > maxWidth {
-		str = str[:maxWidth-3] + "..." // Truncate and add ellipsis
+	marker := ""
+	if d.marked[string(i)] {
+		marker = markMarker
+		maxWidth -= len(marker)
 	}
 
+	if d.lines > 1 {
+		d.renderWrapped(w, m, index, str, offset, maxWidth, marker)
+		return
+	}
+
+	// Trim the JSON string if it exceeds the model width
+	str, visibleLen := truncateToWidth(str, maxWidth)
+
 	fn := itemStyle.Render
+	style := itemStyle
 	if index == m.Index() {
+		style = selectedItemStyle
 		fn = func(s ...string) string {
 			return selectedItemStyle.Render("> " + strings.Join(s, " "))
 		}
 	}
 
-	fmt.Fprint(w, fn(str))
+	fmt.Fprint(w, fn(marker+highlightMatches(m, index, str, offset, visibleLen, style)))
 }
 
 // keyMap defines a set of keybindings. To work for help it must satisfy
 // key.Map. It could also very easily be a map[string]key.Binding.
 type TableDataKeyMap struct {
-	Up        key.Binding
-	Down      key.Binding
-	Help      key.Binding
-	Quit      key.Binding
-	SelectRow key.Binding
+	Up             key.Binding
+	Down           key.Binding
+	Top            key.Binding
+	Bottom         key.Binding
+	ScrollLeft     key.Binding
+	ScrollRight    key.Binding
+	ToggleGrid     key.Binding
+	FlattenGrid    key.Binding
+	Sort           key.Binding
+	NewestFirst    key.Binding
+	NewItem        key.Binding
+	Refresh        key.Binding
+	Rescan         key.Binding
+	DeleteFiltered key.Binding
+	Import         key.Binding
+	CopyCLI        key.Binding
+	Schema         key.Binding
+	FilterBy       key.Binding
+	ScanFilter     key.Binding
+	ScanIndex      key.Binding
+	Live           key.Binding
+	LiveFaster     key.Binding
+	LiveSlower     key.Binding
+	Stream         key.Binding
+	Sample         key.Binding
+	Wrap           key.Binding
+	Mark           key.Binding
+	ClearMarks     key.Binding
+	CopyMarked     key.Binding
+	Diff           key.Binding
+	GetItem        key.Binding
+	Query          key.Binding
+	NextPage       key.Binding
+	Help           key.Binding
+	Quit           key.Binding
+	SelectRow      key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
@@ -79,9 +200,20 @@ func (k TableDataKeyMap) ShortHelp() []key.Binding {
 // key.Map interface.
 func (k TableDataKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down},   // first column
-		{k.SelectRow},    // second column
-		{k.Help, k.Quit}, // third column
+		{k.Up, k.Down, k.Top, k.Bottom},       // first column
+		{k.ScrollLeft, k.ScrollRight, k.Wrap}, // second column
+		{k.SelectRow, k.ToggleGrid, k.FlattenGrid, k.Sort, k.NewestFirst, k.FilterBy, k.ScanFilter, k.ScanIndex, k.GetItem, k.Query, k.NextPage, k.NewItem, k.Import, k.DeleteFiltered, k.Refresh, k.Rescan, k.Sample, k.CopyCLI, k.Schema}, // third column
+		{k.Mark, k.ClearMarks, k.CopyMarked, k.Diff},
+		{k.Live, k.LiveFaster, k.LiveSlower, k.Stream},
+		{k.Help, k.Quit}, // fourth column
+	}
+}
+
+// disableWrites marks the bindings of write actions as disabled in the help
+func (k *TableDataKeyMap) disableWrites() {
+	for _, binding := range []*key.Binding{&k.NewItem, &k.Import, &k.DeleteFiltered} {
+		help := binding.Help()
+		binding.SetHelp(help.Key, help.Desc+" (read-only)")
 	}
 }
 
@@ -94,13 +226,138 @@ var tableDataKeys = TableDataKeyMap{
 		key.WithKeys("down", "j"),
 		key.WithHelp("↓/j", "move down"),
 	),
+	Top: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("g/home", "go to top"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("G/end", "go to bottom"),
+	),
+	ScrollLeft: key.NewBinding(
+		key.WithKeys("shift+left", "H"),
+		key.WithHelp("shift+←/H", "scroll left"),
+	),
+	ScrollRight: key.NewBinding(
+		key.WithKeys("shift+right", "L"),
+		key.WithHelp("shift+→/L", "scroll right"),
+	),
 	SelectRow: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "select row"),
 	),
+	ToggleGrid: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "toggle grid view"),
+	),
+	FlattenGrid: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "toggle flattened grid columns"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort by attribute"),
+	),
+	NewestFirst: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "newest first (sort key descending)"),
+	),
+	NewItem: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "new item"),
+	),
+	Refresh: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "refresh"),
+	),
+	Rescan: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "rescan, keeping the rows shown"),
+	),
+	DeleteFiltered: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "delete marked or filtered rows"),
+	),
+	Import: key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "import items"),
+	),
+	Schema: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "infer schema"),
+	),
+	FilterBy: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "filter on one attribute"),
+	),
+	ScanFilter: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "scan filter (applied by DynamoDB)"),
+	),
+	ScanIndex: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "scan an index"),
+	),
+	Live: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "toggle live refresh"),
+	),
+	Stream: key.NewBinding(
+		key.WithKeys("W"),
+		key.WithHelp("W", "tail the table's stream"),
+		key.WithDisabled(), // until the table turns out to have a stream
+	),
+	LiveFaster: key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "refresh more often (live)"),
+	),
+	LiveSlower: key.NewBinding(
+		key.WithKeys("+"),
+		key.WithHelp("+", "refresh less often (live)"),
+	),
+	Wrap: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "toggle wrapping rows"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "mark/unmark row"),
+	),
+	ClearMarks: key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "unmark all rows"),
+	),
+	CopyMarked: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy marked rows"),
+	),
+	Diff: key.NewBinding(
+		key.WithKeys("="),
+		key.WithHelp("=", "diff two marked rows"),
+	),
+	Query: key.NewBinding(
+		key.WithKeys("Q"),
+		key.WithHelp("Q", "query a partition"),
+	),
+	NextPage: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next query page"),
+	),
+	GetItem: key.NewBinding(
+		key.WithKeys("K"),
+		key.WithHelp("K", "get item by key"),
+	),
+	Sample: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "toggle sample mode (scan first items only)"),
+	),
+	CopyCLI: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "copy as AWS CLI command"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
-		key.WithHelp("?", "toggle help"),
+		key.WithHelp("?", "all keys"),
 	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "esc", "ctrl+c"),
@@ -112,9 +369,52 @@ type TableDataModel struct {
 	keys          TableDataKeyMap
 	tableData     []list.Item
 	selectedTable string
+	region        string // region of selectedTable
 	client        *dynamodb.Client
 	dataList      list.Model
 	selectedRow   string
+	hOffset       int
+	grid          table.Model
+	gridOffset    int // columns scrolled off the left of the grid
+	showGrid      bool
+	flattenGrid   bool // expand nested attributes of the grid into columns
+	flattenDepth  int  // levels of nested attributes the flattened grid expands
+	dataLoaded    bool
+
+	loading          bool
+	lookingUp        bool // a row or index lookup is running; kept apart from loading so it can't end a scan's spinner
+	loadingIndicator spinner.Model
+	cancelScan       context.CancelCauseFunc
+	tableInfo        *describeCache
+	scanTimeout      time.Duration
+	filterAttribute  string // when set, the list filter only searches this attribute's value
+	scanPageLimit    int    // configured items per scan request, zero to pick one per table
+	scanSegments     int    // configured most parallel scan segments, zero for no limit below the CPU cap
+	sampleSize       int    // items a scan in sample mode stops after
+	sampling         bool   // sample mode: scans stop after sampleSize items
+	sampled          bool   // the loaded rows are a sample, not the whole table
+	partial          string // why the loaded rows are only part of the scan, empty when they are all of it
+
+	liveInterval   time.Duration // live refresh interval, zero when off
+	liveGeneration int
+
+	scanFilter      *tools.FilterExpression // filter DynamoDB applies to scans, if any
+	scanFilterInput string                  // scanFilter as the user typed it
+
+	scanIndex           string            // secondary index scanned instead of the table, if any
+	scanIndexProjection string            // attributes scanIndex holds
+	indexProjections    map[string]string // projection of each index of selectedTable
+
+	query    *tableQuery     // partition the rows were queried from, if any
+	marked   map[string]bool // rows marked for bulk actions
+	wrapRows bool            // rows are wrapped over several lines instead of truncated
+
+	confirmedHeavyScan string // table whose slow scan at its provisioned capacity was confirmed
+
+	refreshingInBackground bool // set on the copy running a background refresh
+
+	sortAttribute  string // attribute the rows are kept sorted by, if any
+	sortDescending bool
 }
 
 func (m TableDataModel) New(client *dynamodb.Client) TableDataModel {
@@ -126,15 +426,27 @@ func (m TableDataModel) New(client *dynamodb.Client) TableDataModel {
 	l.SetShowStatusBar(false)
 	l.Styles.PaginationStyle = paginationStyle
 	l.SetShowHelp(true)
+	themeHelp(&l.Help.Styles)
 	l.SetShowFilter(true)
 	l.KeyMap.Quit.SetKeys("q", "ctrl-c")
 	l.AdditionalFullHelpKeys = func() []key.Binding {
 		return []key.Binding{tableDataKeys.SelectRow}
 	}
 
+	gridStyles := table.DefaultStyles()
+	gridStyles.Selected = gridStyles.Selected.Foreground(lipgloss.Color(theme.SelectedItem))
+
+	grid := table.New(table.WithFocused(true), table.WithStyles(gridStyles))
+
 	return TableDataModel{
 		keys: tableDataKeys,
 
+		grid: grid,
+
+		loadingIndicator: newLoadingIndicator(tools.Config{}),
+
+		tableInfo: newDescribeCache(),
+
 		selectedTable: "",
 
 		client: client,
@@ -143,53 +455,301 @@ func (m TableDataModel) New(client *dynamodb.Client) TableDataModel {
 	}
 }
 
+// setHorizontalOffset scrolls the data rows sideways so each row starts at the
+// given character offset. It stops once the widest row on the page ends at
+// the right edge of the pane.
+func (m *TableDataModel) setHorizontalOffset(offset int) {
+	items := m.dataList.VisibleItems()
+	start, end := m.dataList.Paginator.GetSliceBounds(len(items))
+	widest := 0
+	for _, item := range items[start:end] {
+		widest = max(widest, lipgloss.Width(item.FilterValue()))
+	}
+
+	// The delegate keeps 3 cells for the cursor prefix and padding
+	m.hOffset = max(0, min(offset, widest-(m.dataList.Width()-3)))
+	m.refreshDelegate()
+}
+
+// refreshGrid rebuilds the grid view from the rows currently visible in the
+// data list, so the grid honors any active filter.
+func (m *TableDataModel) refreshGrid() {
+	flattenDepth := 0
+	if m.flattenGrid {
+		flattenDepth = m.flattenDepth
+	}
+	headers, cells := tools.RowsToGrid(listItemsToRows(m.dataList.VisibleItems()), flattenDepth)
+
+	m.gridOffset = max(0, min(m.gridOffset, len(headers)-1))
+	if m.gridOffset > 0 {
+		headers = headers[m.gridOffset:]
+		for i, row := range cells {
+			cells[i] = row[m.gridOffset:]
+		}
+	}
+
+	columns := make([]table.Column, len(headers))
+	for i, header := range headers {
+		width := lipgloss.Width(header)
+		for _, row := range cells {
+			width = max(width, lipgloss.Width(row[i]))
+		}
+		columns[i] = table.Column{Title: header, Width: min(width, maxGridColumnWidth)}
+	}
+
+	gridRows := make([]table.Row, len(cells))
+	for i, row := range cells {
+		gridRows[i] = table.Row(row)
+	}
+
+	// Clear rows first so they never disagree with the column count
+	m.grid.SetRows(nil)
+	m.grid.SetColumns(columns)
+	m.grid.SetRows(gridRows)
+	m.grid.SetCursor(0)
+}
+
+// scrollGrid scrolls the grid sideways by delta columns, keeping the cursor
+// on the same row
+func (m *TableDataModel) scrollGrid(delta int) {
+	cursor := m.grid.Cursor()
+	m.gridOffset += delta
+	m.refreshGrid()
+	m.grid.SetCursor(cursor)
+}
+
+// setRows replaces the loaded rows with a fetched result. A background
+// refresh lands while the user is looking at the rows, so they keep the
+// order the user chose rather than jumping back to scan order.
+func (m *TableDataModel) setRows(items []list.Item) tea.Cmd {
+	cmd := m.dataList.SetItems(items)
+	if m.sortAttribute != "" {
+		return tea.Batch(cmd, m.sortByAttribute(m.sortAttribute, m.sortDescending))
+	}
+	if m.showGrid {
+		m.refreshGrid()
+	}
+	return cmd
+}
+
+// sortByAttribute reorders the loaded rows by the given attribute. The list
+// keeps its filter, so a filtered view stays filtered after sorting, and rows
+// loaded later are sorted the same way.
+func (m *TableDataModel) sortByAttribute(attribute string, descending bool) tea.Cmd {
+	m.sortAttribute = attribute
+	m.sortDescending = descending
+	sorted := tools.SortRowsByAttribute(listItemsToRows(m.dataList.Items()), attribute, descending)
+
+	items := make([]list.Item, len(sorted))
+	for i, row := range sorted {
+		items[i] = tableDataRow(row)
+	}
+
+	cmd := m.dataList.SetItems(items)
+	if m.showGrid {
+		m.refreshGrid()
+	}
+
+	return cmd
+}
+
+// selectedGridRow returns the data row under the grid cursor
+func (m TableDataModel) selectedGridRow() (tableDataRow, bool) {
+	items := m.dataList.VisibleItems()
+	cursor := m.grid.Cursor()
+	if cursor < 0 || cursor >= len(items) {
+		return "", false
+	}
+
+	row, ok := items[cursor].(tableDataRow)
+	return row, ok
+}
+
+// startScan cancels any scan still in flight and starts loading the given table
+func (m *TableDataModel) startScan(tableName string) tea.Cmd {
+	m.stopScan()
+
+	ctx, cancel := context.WithCancelCause(appContext)
+	m.cancelScan = cancel
+
+	return trackWork(m.fetchAllData(ctx, tableName))
+}
+
+// startRefresh rescans the table from DynamoDB, bypassing both the data cache
+// and the cached table metadata
+func (m *TableDataModel) startRefresh(tableName string) tea.Cmd {
+	m.tableInfo.invalidate(tableName)
+
+	return m.startFetch(tableName, false)
+}
+
+// startBackgroundRefresh rescans the table after cached rows were shown, so
+// the list is updated in place once fresh data arrives
+func (m *TableDataModel) startBackgroundRefresh(tableName string) tea.Cmd {
+	return m.startFetch(tableName, true)
+}
+
+// startFetch scans the table from DynamoDB without looking at the data cache
+func (m *TableDataModel) startFetch(tableName string, background bool) tea.Cmd {
+	m.stopScan()
+
+	ctx, cancel := context.WithCancelCause(appContext)
+	m.cancelScan = cancel
+
+	data := *m
+	if background {
+		// Refreshes follow rows already shown, so a slow scan isn't asked
+		// about, and one cut short leaves the complete cache as it is
+		data.confirmedHeavyScan = tableName
+		data.refreshingInBackground = true
+	}
+	return trackWork(func() tea.Msg {
+		msg := data.fetchAndCacheTableData(ctx, tableName, nil)
+		if fetched, ok := msg.(DataFetchedMsg); ok {
+			fetched.background = background
+			return fetched
+		}
+		return msg
+	})
+}
+
+// stopScan cancels the scan in flight, if any
+func (m *TableDataModel) stopScan() {
+	if m.cancelScan != nil {
+		m.cancelScan(nil)
+		m.cancelScan = nil
+	}
+}
+
+// interruptScan stops the scan in flight like stopScan, but has it return
+// the rows it read so far, marked as partial results
+func (m *TableDataModel) interruptScan() {
+	if m.cancelScan != nil {
+		m.cancelScan(errScanStopped)
+		m.cancelScan = nil
+	}
+}
+
 // fetchAllData with cache fallback and fetch if cache is missing
-func (m TableDataModel) fetchAllData(tableName string) tea.Cmd {
+func (m TableDataModel) fetchAllData(ctx context.Context, tableName string) tea.Cmd {
 	return func() tea.Msg {
-		// Attempt to load cached data
-		cache, err := tools.LoadCache(tableDataCacheFilePath(tableName))
-		if err == nil && time.Since(cache.Updated) < CacheDuration {
-			// Return cached data immediately
-			go m.refreshTableDataCacheInBackground(tableName) // Trigger background fetch
+		// Attempt to load cached data. The cache holds the whole table, so
+		// filtered and index scans always go to DynamoDB.
+		cache, err := tools.LoadCache(tableDataCacheFilePath(m.region, tableName))
+		if err == nil && time.Since(cache.Updated) < CacheDuration && !m.partialScan() {
+			if !cache.Complete {
+				// An interrupted scan continues where its segments got to
+				log.Printf("Resuming the scan of %s after %d cached items", tableName, len(cache.Data))
+				return m.fetchAndCacheTableData(ctx, tableName, cache)
+			}
+
+			// Return cached data immediately; the handler then triggers a
+			// background refresh
 
 			var items []list.Item
 			for _, value := range cache.Data {
 				items = append(items, tableDataRow(value))
 			}
-			return DataFetchedMsg(items)
+			return DataFetchedMsg{region: m.region, table: tableName, items: items, fromCache: true}
 		}
 
 		// If cache is missing or outdated, fetch fresh data synchronously
-		return m.fetchAndCacheTableData(tableName)
+		return m.fetchAndCacheTableData(ctx, tableName, nil)
 	}
 }
 
-// fetchAndCacheTableData performs an immediate fetch from DynamoDB, caches the result, and returns it
-func (m TableDataModel) fetchAndCacheTableData(tableName string) tea.Msg {
-	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+// fetchAndCacheTableData performs an immediate fetch from DynamoDB, caches the result, and returns it.
+// Given the cache of an interrupted scan, it continues that scan instead of starting over.
+func (m TableDataModel) fetchAndCacheTableData(parent context.Context, tableName string, resume *tools.Cache) tea.Msg {
+	ctx, cancel := context.WithTimeout(parent, m.scanTimeout)
 	defer cancel()
 
-	// Describe the table to get primary key schema
-	tableInfo, err := m.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
-		TableName: &tableName,
-	})
-	if err != nil {
+	// Describe the table to get primary key schema. Some roles may scan a
+	// table but not describe it; they still get its rows, read sequentially
+	// since neither the key schema nor the table size is known.
+	var warning string
+	tableInfo, err := m.describeTable(ctx, tableName)
+	described := err == nil
+	if err != nil && !isAccessDenied(err) {
 		log.Printf("Failed to describe table: %v", err)
-		return FetchErrorMsg{err}
+		return m.scanErrorMsg(err)
+	}
+	if !described {
+		log.Printf("Not allowed to describe table %s, scanning without its schema: %v", tableName, err)
+		warning = "Not allowed to describe the table — scanned without its key schema; editing, deleting and key lookups may fail"
+		tableInfo = &types.TableDescription{}
+	} else if !scannable(tableInfo) {
+		return FetchErrorMsg{tableNotReadyError(tableInfo)}
 	}
 
 	// Retrieve the primary key attributes
-	partitionKey, sortKey, err := extractPrimaryKeyAttributes(tableInfo.Table.KeySchema)
-	if err != nil {
-		log.Printf("Failed to retrieve primary key schema: %v", err)
-		return FetchErrorMsg{err}
+	var partitionKey string
+	var sortKey *string
+	if described {
+		partitionKey, sortKey, err = extractPrimaryKeyAttributes(tableInfo.KeySchema)
+		if err != nil {
+			log.Printf("Failed to retrieve primary key schema: %v", err)
+			return FetchErrorMsg{err}
+		}
 	}
 
-	// Get the number of available CPU cores
-	numSegments := runtime.NumCPU() / 2
-	log.Printf("Using %d segments for parallel scan", numSegments)
+	// Binary keys are kept whole in the rows, so the items can be written to
+	keyAttributes := tools.KeyAttributes(tableInfo)
+
+	pageLimit := m.pageLimit(tableInfo)
+	log.Printf("Scanning %d items per page", pageLimit)
 
+	// On-demand tables can take a fast scan. A provisioned table's scan
+	// uses part of its read capacity, with few segments if it is low, and
+	// is confirmed first when that makes it slow.
+	maxSegments := m.maxSegments()
+	var limiter *capacityLimiter
+	rcu, provisioned := readCapacity(tableInfo, m.scanIndex)
+	if provisioned {
+		rate := rcu * provisionedScanShare
+		estimate := estimatedScanRCU(aws.ToInt64(tableInfo.TableSizeBytes))
+		if !m.sampling && m.confirmedHeavyScan != tableName && time.Duration(estimate/rate*float64(time.Second)) > heavyScanDuration {
+			return HeavyScanMsg{region: m.region, table: tableName, estimate: estimate, rate: rate, resuming: resume != nil}
+		}
+		maxSegments = provisionedSegments(maxSegments, rcu)
+		limiter = newCapacityLimiter(rate)
+		log.Printf("Provisioned table with %.0f RCU, scanning at up to %.0f RCU/s", rcu, rate)
+	}
+
+	// Small tables are scanned with few segments; large ones get up to
+	// half the CPU cores, or the configured maximum if lower. A table that
+	// couldn't be described has no size and is scanned in one segment.
+	numSegments := chooseSegments(aws.ToInt64(tableInfo.TableSizeBytes), maxSegments)
+
+	// A resumed scan keeps the segments it was started with, as their keys
+	// only continue the same split of the table
 	var allItems []list.Item // Store data as single-line JSON strings
+	progress := make([]tools.ScanSegmentKey, numSegments)
+	startKeys := make([]map[string]types.AttributeValue, numSegments)
+	if resume != nil {
+		if keys, err := resumeStartKeys(resume.Segments); err != nil {
+			log.Printf("Can't resume the scan of %s, starting over: %v", tableName, err)
+		} else {
+			numSegments = len(keys)
+			progress, startKeys = resume.Segments, keys
+			for _, value := range resume.Data {
+				allItems = append(allItems, tableDataRow(value))
+			}
+		}
+	}
+	resumed := len(allItems)
+	log.Printf("Using %d segments for parallel scan", numSegments)
+
+	// In sample mode the segments stop as soon as they have sampleSize
+	// items between them
+	if m.sampling {
+		pageLimit = min(pageLimit, int32(m.sampleSize))
+	}
+	segmentCtx, stopSegments := context.WithCancel(ctx)
+	defer stopSegments()
+
+	var consumedCapacity float64
+	var sampled bool
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 	errChan := make(chan error, numSegments)
@@ -199,19 +759,40 @@ func (m TableDataModel) fetchAndCacheTableData(tableName string) tea.Msg {
 		wg.Add(1)
 		go func(segment int) {
 			defer wg.Done()
-			var startKey map[string]types.AttributeValue
+			if progress[segment].Done {
+				return
+			}
+			startKey := startKeys[segment]
 
 			for {
 				// Prepare scan input with the segment details and validated ExclusiveStartKey
 				input := &dynamodb.ScanInput{
 					TableName:         &tableName,
-					Limit:             aws.Int32(100),
+					Limit:             aws.Int32(pageLimit),
 					Segment:           aws.Int32(int32(segment)),
 					TotalSegments:     aws.Int32(int32(numSegments)),
 					ExclusiveStartKey: validateExclusiveStartKey(startKey, partitionKey, sortKey),
+					// Report the read capacity used so its cost can be shown
+					ReturnConsumedCapacity: types.ReturnConsumedCapacityTotal,
+				}
+				if m.scanIndex != "" {
+					// Index pages continue from keys that include the
+					// index's own key attributes, so they're passed as is
+					input.IndexName = aws.String(m.scanIndex)
+					input.ExclusiveStartKey = startKey
+				}
+				if !described {
+					// Without the key schema there's nothing to validate
+					// the start key against
+					input.ExclusiveStartKey = startKey
+				}
+				if m.scanFilter != nil {
+					input.FilterExpression = aws.String(m.scanFilter.Expression)
+					input.ExpressionAttributeNames = m.scanFilter.Names
+					input.ExpressionAttributeValues = m.scanFilter.Values
 				}
 
-				output, err := m.client.Scan(ctx, input)
+				output, err := m.client.Scan(segmentCtx, input)
 				if err != nil {
 					errChan <- err
 					return
@@ -220,7 +801,7 @@ func (m TableDataModel) fetchAndCacheTableData(tableName string) tea.Msg {
 				// Transform items into JSON strings
 				var jsonItems []list.Item
 				for _, item := range output.Items {
-					mapItem, err := tools.DynamoItemToMap(item)
+					mapItem, err := tools.DynamoItemToRowMap(item, keyAttributes)
 					if err != nil {
 						log.Printf("Error converting item: %v", err)
 						continue
@@ -236,13 +817,30 @@ func (m TableDataModel) fetchAndCacheTableData(tableName string) tea.Msg {
 				// Append transformed items to the shared allItems slice
 				mu.Lock()
 				allItems = append(allItems, jsonItems...)
+				var consumed float64
+				if output.ConsumedCapacity != nil {
+					consumed = aws.ToFloat64(output.ConsumedCapacity.CapacityUnits)
+					consumedCapacity += consumed
+				}
+				if m.sampling && len(allItems) >= m.sampleSize {
+					allItems = allItems[:m.sampleSize]
+					sampled = true
+					stopSegments()
+				}
+				progress[segment] = segmentProgress(output.LastEvaluatedKey)
+				done := sampled
 				mu.Unlock()
 
 				// Check if more items are available
-				if output.LastEvaluatedKey == nil {
+				if done || output.LastEvaluatedKey == nil {
 					break
 				}
 
+				if err := limiter.wait(segmentCtx, consumed); err != nil {
+					errChan <- err
+					return
+				}
+
 				// Update startKey for the next scan in this segment
 				startKey = output.LastEvaluatedKey
 			}
@@ -253,33 +851,306 @@ func (m TableDataModel) fetchAndCacheTableData(tableName string) tea.Msg {
 	wg.Wait()
 	close(errChan)
 
-	// Check if there were any errors
-	if err := <-errChan; err != nil {
-		log.Printf("Error in parallel scan: %v", err)
-		return FetchErrorMsg{err}
+	// Check if there were any errors. Once the sample is complete, the other
+	// segments fail because they were stopped. Rows read before the scan
+	// was stopped, timed out or lost a segment are kept as partial results,
+	// unless a newer scan replaced this one.
+	var failed []error
+	for err := range errChan {
+		failed = append(failed, err)
+	}
+	msg := DataFetchedMsg{region: m.region, table: tableName, items: allItems, consumedCapacity: consumedCapacity, sampled: sampled, warning: warning, resumed: resumed}
+	if limiter != nil {
+		msg.paced = limiter.rate
+	}
+	if len(failed) > 0 && !sampled {
+		err := failed[0]
+		msg.stopped = errors.Is(context.Cause(parent), errScanStopped)
+
+		// Keep how far the scan got, so loading the table again resumes it
+		if !m.partialScan() && !m.refreshingInBackground && len(allItems) > 0 {
+			if err := tools.SaveScanProgress(allItems, progress, CacheDir, tableDataCacheFilePath(m.region, tableName)); err != nil {
+				log.Println("Failed to save scan progress:", err)
+			} else {
+				msg.resumable = true
+			}
+		}
+
+		if len(allItems) == 0 || (errors.Is(err, context.Canceled) && !msg.stopped) {
+			log.Printf("Error in parallel scan: %v", err)
+			return m.scanErrorMsg(err)
+		}
+
+		log.Printf("Scan of %s ended early after %d items: %v", tableName, len(allItems), err)
+		msg.partial = partialReason(err, msg.stopped, len(failed), numSegments)
+		if !msg.stopped {
+			msg.err = m.scanError(err)
+		}
+		return msg
+	}
+
+	// Cache the fetched data, unless part of the table was left out
+	if !m.partialScan() {
+		if err := tools.SaveCache(allItems, CacheDir, tableDataCacheFilePath(m.region, tableName)); err != nil {
+			log.Println("Failed to save cache:", err)
+		}
+	}
+
+	return msg
+}
+
+// segmentProgress records where a segment continues after a page that ended
+// with lastKey, which is nil once the segment was read to its end
+func segmentProgress(lastKey map[string]types.AttributeValue) tools.ScanSegmentKey {
+	if lastKey == nil {
+		return tools.ScanSegmentKey{Done: true}
+	}
+	startKey, err := tools.MarshalTypedJSON(lastKey)
+	if err != nil {
+		// Resuming would read the segment again from its start
+		log.Printf("Failed to record scan progress: %v", err)
+	}
+	return tools.ScanSegmentKey{StartKey: startKey}
+}
+
+// resumeStartKeys decodes the keys an interrupted scan's segments continue
+// from; a segment without one starts from its beginning
+func resumeStartKeys(segments []tools.ScanSegmentKey) ([]map[string]types.AttributeValue, error) {
+	if len(segments) == 0 {
+		return nil, errors.New("no segments recorded")
+	}
+
+	keys := make([]map[string]types.AttributeValue, len(segments))
+	for i, segment := range segments {
+		if segment.StartKey == "" {
+			continue
+		}
+		key, err := tools.UnmarshalTypedKey(segment.StartKey)
+		if err != nil {
+			return nil, fmt.Errorf("segment %d: %w", i, err)
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// partialReason says briefly why a scan ended before reading the whole table
+func partialReason(err error, stopped bool, failedSegments, numSegments int) string {
+	switch {
+	case stopped:
+		return "scan stopped"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "scan timed out"
+	case numSegments > 1:
+		return fmt.Sprintf("%d of %d segments failed", failedSegments, numSegments)
+	default:
+		return "scan failed"
+	}
+}
+
+// maxSegments returns the most parallel segments a scan may use: half the CPU
+// cores, capped by the configured maximum
+func (m TableDataModel) maxSegments() int {
+	segments := runtime.NumCPU() / 2
+	if m.scanSegments > 0 {
+		segments = min(segments, m.scanSegments)
+	}
+	return segments
+}
+
+// chooseSegments picks how many parallel scan segments to use for a table of
+// sizeBytes: one per segmentBytes of data, at least one and at most
+// maxSegments. DynamoDB only updates sizes every few hours, so an unknown or
+// zero size yields a single segment.
+func chooseSegments(sizeBytes int64, maxSegments int) int {
+	if maxSegments < 1 {
+		maxSegments = 1
+	}
+	if sizeBytes <= 0 {
+		return 1
+	}
+
+	segments := (sizeBytes + segmentBytes - 1) / segmentBytes
+	return int(min(segments, int64(maxSegments)))
+}
+
+// pageLimit returns the number of items each scan request asks for. Unless it
+// was configured, it is sized so a page of average items fills the 1 MB that
+// DynamoDB returns at most, avoiding requests cut short by the size cap.
+func (m TableDataModel) pageLimit(tableInfo *types.TableDescription) int32 {
+	if m.scanPageLimit != 0 {
+		return int32(m.scanPageLimit)
+	}
+
+	itemCount := aws.ToInt64(tableInfo.ItemCount)
+	tableSize := aws.ToInt64(tableInfo.TableSizeBytes)
+	if itemCount == 0 || tableSize == 0 {
+		return defaultScanPageLimit
+	}
+
+	averageSize := max(tableSize/itemCount, 1)
+	return int32(min(max(scanPageBytes/averageSize, MinScanPageLimit), MaxScanPageLimit))
+}
+
+// scanErrorMsg reports a failed scan, unless it failed because it was cancelled
+func (m TableDataModel) scanErrorMsg(err error) tea.Msg {
+	if errors.Is(err, context.Canceled) {
+		log.Println("Scan cancelled")
+		return ScanCancelledMsg{}
+	}
+	return FetchErrorMsg{m.scanError(err)}
+}
+
+// scanError explains a timed out scan; other errors are returned as they are
+func (m TableDataModel) scanError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("scan timed out after %s — increase LAZYDYNAMO_SCAN_TIMEOUT", m.scanTimeout)
+	}
+	return err
+}
+
+// compressDataCaches has table data cached gzip compressed, unless the
+// uncompressedCache setting turns it off
+var compressDataCaches = true
+
+// Helper function to generate a unique cache file path for each table, keeping
+// tables of the same name in different regions apart. Scans can be large, so
+// they are cached compressed unless configured otherwise.
+func tableDataCacheFilePath(region, tableName string) string {
+	path := filepath.Join(CacheDir, fmt.Sprintf("%s_%s_data_cache.json", region, tableName))
+	if compressDataCaches {
+		path += ".gz"
+	}
+	return path
+}
+
+// setScanFilter parses and applies a filter expression to the next scans and
+// rescans the table. An empty input removes the filter.
+func (m *TableDataModel) setScanFilter(input string) (tea.Cmd, error) {
+	var filter *tools.FilterExpression
+	if input != "" {
+		var err error
+		if filter, err = tools.ParseFilterExpression(input); err != nil {
+			return nil, err
+		}
+	}
+
+	m.scanFilter = filter
+	m.scanFilterInput = input
+	m.loading = true
+	m.dataLoaded = false
+
+	return tea.Batch(m.dataList.SetItems(nil), m.startFetch(m.selectedTable, false), m.loadingIndicator.Tick), nil
+}
+
+// setFilterAttribute limits the list filter to the value of one attribute.
+// An empty attribute goes back to filtering on the whole row.
+func (m *TableDataModel) setFilterAttribute(attribute string) {
+	m.filterAttribute = attribute
+	m.dataList.ResetFilter()
+	if attribute == "" {
+		m.dataList.Filter = list.DefaultFilter
+		return
+	}
+	m.dataList.Filter = tools.AttributeFilter(attribute)
+}
+
+// countLabel describes how many rows are loaded and, while a filter narrows
+// them down, how many of those are shown, e.g. "showing 240 of 1,042"
+func (m TableDataModel) countLabel() string {
+	if !m.dataLoaded {
+		return ""
+	}
+
+	total := len(m.dataList.Items())
+	var partial string
+	if reason := m.partialReason(); reason != "" {
+		partial = "⚠ partial results (" + reason + ") — "
+	}
+	label := fmt.Sprintf("showing %s of %s", formatCount(len(m.dataList.VisibleItems())), formatCount(total))
+	if m.dataList.FilterState() == list.Unfiltered {
+		label = formatCount(total) + " rows"
+		if m.sampled {
+			label = fmt.Sprintf("sampled first %s items", formatCount(total))
+		}
+	}
+	if m.query != nil {
+		label = m.query.described + " — " + label
+		if m.query.more() {
+			label += ", more pages (n)"
+		}
+	}
+	if marked := len(m.markedRows()); marked > 0 {
+		label += fmt.Sprintf(", %s marked", formatCount(marked))
+	}
+	return partial + label
+}
+
+// partialReason says why the loaded rows aren't the whole scan, or returns an
+// empty string when they are
+func (m TableDataModel) partialReason() string {
+	if m.partial != "" {
+		return m.partial
 	}
+	if m.sampled {
+		return "sampled"
+	}
+	return ""
+}
 
-	// Cache the fetched data
-	if err := tools.SaveCache(allItems, CacheDir, tableDataCacheFilePath(tableName)); err != nil {
-		log.Println("Failed to save cache:", err)
+// formatCount writes n with thousands separators
+func formatCount(n int) string {
+	digits := strconv.Itoa(n)
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
 	}
+	return digits
+}
 
-	return DataFetchedMsg(allItems)
+// gotoRow moves the selection to the given row of the list or grid
+func (m *TableDataModel) gotoRow(index int) {
+	if index < 0 {
+		return
+	}
+	if m.showGrid {
+		m.grid.SetCursor(index)
+		return
+	}
+	m.dataList.Select(index)
 }
 
-// refreshTableDataCacheInBackground fetches fresh data and updates the cache in the background
-func (m TableDataModel) refreshTableDataCacheInBackground(tableName string) {
-	// Perform a fetch and cache update in the background
-	msg := m.fetchAndCacheTableData(tableName)
-	if fetchMsg, ok := msg.(DataFetchedMsg); ok {
-		// Handle the result if needed (e.g., update the UI with fresh data)
-		log.Println("Cache refreshed in background for table data:", fetchMsg)
+// selectTable points the model at a table, switching to the client of its
+// region. Table metadata is cached per region, so it is dropped when the
+// region changes.
+func (m *TableDataModel) selectTable(client *dynamodb.Client, region, tableName string) {
+	if region != m.region {
+		m.tableInfo = newDescribeCache()
+	}
+	if !m.isSelected(region, tableName) {
+		m.stopLive()
+		m.scanFilter = nil
+		m.scanFilterInput = ""
+		m.scanIndex = ""
+		m.scanIndexProjection = ""
+		m.indexProjections = nil
+		m.sortAttribute = ""
+		m.gridOffset = 0
+		m.setMarked(nil)
+		m.query = nil
+		if m.filterAttribute != "" {
+			m.setFilterAttribute("")
+		}
 	}
+	m.client = client
+	m.region = region
+	m.selectedTable = tableName
 }
 
-// Helper function to generate a unique cache file path for each table
-func tableDataCacheFilePath(tableName string) string {
-	return fmt.Sprintf("%s/%s_data_cache.json", CacheDir, tableName)
+// isSelected reports whether a result for the table in region belongs to the
+// selected table. With several regions listed, tables of the same name can
+// be selected one after the other, so the name alone isn't enough.
+func (m TableDataModel) isSelected(region, tableName string) bool {
+	return region == m.region && tableName == m.selectedTable
 }
 
 // extractPrimaryKeyAttributes retrieves primary key attributes and their types from the KeySchema
@@ -0,0 +1,217 @@
+package lazydynamo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/components"
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestChooseSegments(t *testing.T) {
+	tests := []struct {
+		name        string
+		sizeBytes   int64
+		maxSegments int
+		want        int
+	}{
+		{"empty table", 0, 8, 1},
+		{"unknown size", -1, 8, 1},
+		{"one byte", 1, 8, 1},
+		{"exactly one segment", segmentBytes, 8, 1},
+		{"just over one segment", segmentBytes + 1, 8, 2},
+		{"several segments", 3 * segmentBytes, 8, 3},
+		{"capped by max", 100 * segmentBytes, 8, 8},
+		{"max of one", 100 * segmentBytes, 1, 1},
+		{"max below one", 100 * segmentBytes, 0, 1},
+		{"negative max", 100 * segmentBytes, -4, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chooseSegments(tt.sizeBytes, tt.maxSegments); got != tt.want {
+				t.Errorf("chooseSegments(%d, %d) = %d, want %d", tt.sizeBytes, tt.maxSegments, got, tt.want)
+			}
+		})
+	}
+}
+
+// blockingDynamoDB returns a client whose requests hang until they are
+// cancelled, reporting each request as it arrives and once it is cancelled
+func blockingDynamoDB(t *testing.T) (client *dynamodb.Client, started, cancelled <-chan struct{}) {
+	startedCh := make(chan struct{}, 10)
+	cancelledCh := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The connection is only watched for closing once the body was read
+		io.Copy(io.Discard, r.Body)
+		startedCh <- struct{}{}
+		<-r.Context().Done()
+		cancelledCh <- struct{}{}
+	}))
+	t.Cleanup(server.Close)
+
+	client = dynamodb.New(dynamodb.Options{
+		Region:           "us-east-1",
+		BaseEndpoint:     aws.String(server.URL),
+		Credentials:      credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		RetryMaxAttempts: 1,
+	})
+	return client, startedCh, cancelledCh
+}
+
+func TestSwitchingTablesCancelsScan(t *testing.T) {
+	CacheDir = t.TempDir()
+	client, started, cancelled := blockingDynamoDB(t)
+
+	m := MainModel{
+		state:           ViewingData,
+		collectionsList: list.New(nil, itemDelegate{}, 10, 10),
+		viewRowModel:    ViewRowModel{}.New(),
+	}
+	m.tableDataModel = TableDataModel{}.New(client)
+	m.tableDataModel.scanTimeout = time.Minute
+
+	// Start scanning the first table
+	m.tableDataModel.selectTable(client, "us-east-1", "first")
+	firstScan := m.tableDataModel.startScan("first")
+	firstResult := make(chan tea.Msg, 1)
+	go func() { firstResult <- firstScan() }()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the first scan never reached DynamoDB")
+	}
+
+	// Switching to the second table cancels the first scan
+	m.tableDataModel.selectTable(client, "us-east-1", "second")
+	m.tableDataModel.startScan("second")
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the first scan's request was not cancelled")
+	}
+
+	var msg tea.Msg
+	select {
+	case msg = <-firstResult:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the first scan did not return")
+	}
+	if _, ok := msg.(ScanCancelledMsg); !ok {
+		t.Fatalf("first scan returned %T, want ScanCancelledMsg", msg)
+	}
+
+	updated, _ := m.Update(msg)
+	if err := updated.(MainModel).lastErr; err != nil {
+		t.Errorf("cancelled scan reported an error: %v", err)
+	}
+
+	// A result of the first table arriving late doesn't replace the rows
+	stale := DataFetchedMsg{region: "us-east-1", table: "first", items: []list.Item{tableDataRow(`{"id":"stale"}`)}}
+	updated, _ = updated.(MainModel).Update(stale)
+	if items := updated.(MainModel).tableDataModel.dataList.Items(); len(items) != 0 {
+		t.Errorf("stale rows of the first table were shown: %v", items)
+	}
+}
+
+func TestFailedScanKeepsPartialResultsAndResumes(t *testing.T) {
+	CacheDir = t.TempDir()
+	var recovered atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch {
+		case strings.HasSuffix(r.Header.Get("X-Amz-Target"), "DescribeTable"):
+			io.WriteString(w, `{"Table":{"TableName":"orders","TableStatus":"ACTIVE","KeySchema":[{"AttributeName":"id","KeyType":"HASH"}]}}`)
+		case !strings.Contains(string(body), "ExclusiveStartKey"):
+			io.WriteString(w, `{"Items":[{"id":{"S":"1"}}],"LastEvaluatedKey":{"id":{"S":"1"}}}`)
+		case recovered.Load():
+			io.WriteString(w, `{"Items":[{"id":{"S":"2"}}]}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"__type":"com.amazon.coral.validate#ValidationException","message":"boom"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := dynamodb.New(dynamodb.Options{
+		Region:           "us-east-1",
+		BaseEndpoint:     aws.String(server.URL),
+		Credentials:      credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		RetryMaxAttempts: 1,
+	})
+	m := TableDataModel{}.New(client)
+	m.scanTimeout = time.Minute
+	m.selectTable(client, "us-east-1", "orders")
+
+	msg, ok := m.fetchAndCacheTableData(context.Background(), "orders", nil).(DataFetchedMsg)
+	if !ok {
+		t.Fatalf("failed scan returned %T, want DataFetchedMsg with the rows read", msg)
+	}
+	if len(msg.items) != 1 || msg.partial != "scan failed" || msg.err == nil {
+		t.Errorf("got %d items, partial %q, err %v; want 1 item marked as a failed scan", len(msg.items), msg.partial, msg.err)
+	}
+	cache, err := tools.LoadCache(tableDataCacheFilePath("us-east-1", "orders"))
+	if err != nil || cache.Complete || len(cache.Segments) != 1 || cache.Segments[0].StartKey != `{"id":{"S":"1"}}` {
+		t.Fatalf("scan progress wasn't cached to resume from: %+v, %v", cache, err)
+	}
+
+	// Loading the table again continues from the failed page
+	recovered.Store(true)
+	msg, ok = m.fetchAllData(context.Background(), "orders")().(DataFetchedMsg)
+	if !ok || msg.fromCache || msg.partial != "" || msg.resumed != 1 || len(msg.items) != 2 {
+		t.Fatalf("resumed scan got %+v, want both items with 1 resumed", msg)
+	}
+	if cache, err := tools.LoadCache(tableDataCacheFilePath("us-east-1", "orders")); err != nil || !cache.Complete {
+		t.Errorf("completed scan wasn't cached as complete: %+v, %v", cache, err)
+	}
+}
+
+func TestBatchDeleteAsksForTheTableName(t *testing.T) {
+	m := MainModel{
+		state:           ViewingData,
+		collectionsList: list.New(nil, itemDelegate{}, 10, 10),
+		viewRowModel:    ViewRowModel{}.New(),
+		confirmDialog:   components.NewConfirmDialog(BoxActiveColor),
+	}
+	m.tableDataModel = TableDataModel{}.New(nil)
+	m.tableDataModel.selectTable(nil, "us-east-1", "orders")
+	m.tableDataModel.tableInfo.put("orders", &types.TableDescription{
+		TableName:   aws.String("orders"),
+		TableStatus: types.TableStatusActive,
+		KeySchema:   []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}},
+	})
+	m.tableDataModel.dataList.SetItems([]list.Item{tableDataRow(`{"id":"1"}`), tableDataRow(`{"id":"2"}`)})
+	m.tableDataModel.toggleMark()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	if cmd == nil {
+		t.Fatal("X on a marked row didn't preview the delete")
+	}
+	preview, ok := cmd().(WritePreviewMsg)
+	if !ok || preview.err != nil {
+		t.Fatalf("delete preview returned %#v", preview)
+	}
+	updated, _ = updated.(MainModel).Update(preview)
+
+	// A bare y doesn't confirm; only the table name does
+	updated, _ = updated.(MainModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if !updated.(MainModel).confirmDialog.Active() {
+		t.Fatal("batch delete was confirmed without typing the table name")
+	}
+}
@@ -0,0 +1,61 @@
+package lazydynamo
+
+import (
+	"log"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Colors of the pane borders, set from the theme
+var (
+	BoxActiveColor  = lipgloss.Color("10")
+	BoxDefaultColor = lipgloss.Color("#ffffff")
+)
+
+// theme is the active color theme
+var theme, _ = tools.LoadTheme("", "")
+
+// loadTheme applies the configured theme. An unusable theme is logged and the
+// default one is kept.
+func loadTheme(appConfig tools.Config) {
+	t, err := tools.LoadTheme(appConfig.Theme, appConfig.ThemeFile)
+	if err != nil {
+		log.Printf("Ignoring theme: %v", err)
+		return
+	}
+	applyTheme(t)
+}
+
+// applyTheme rebuilds the shared styles from t. Models built afterwards pick
+// the colors up; it is meant to run once, before the models are created.
+func applyTheme(t tools.Theme) {
+	theme = t
+
+	BoxActiveColor = lipgloss.Color(t.ActiveBorder)
+	BoxDefaultColor = lipgloss.Color(t.DefaultBorder)
+
+	selectedItemStyle = selectedItemStyle.Foreground(lipgloss.Color(t.SelectedItem))
+	spinnerStyle = spinnerStyle.Foreground(lipgloss.Color(t.Spinner))
+
+	statusBarStyle = statusBarStyle.
+		Foreground(lipgloss.Color(t.StatusBarForeground)).
+		Background(lipgloss.Color(t.StatusBarBackground))
+	statusSegmentStyle = statusBarStyle.Padding(0, 1)
+	statusModeStyle = statusModeStyle.Background(lipgloss.Color(t.Mode))
+	readOnlyBadgeStyle = readOnlyBadgeStyle.Background(lipgloss.Color(t.Error))
+	errorBannerStyle = errorBannerStyle.Background(lipgloss.Color(t.Error))
+}
+
+// themeHelp colors the keys and descriptions of a help line from the theme
+func themeHelp(styles *help.Styles) {
+	if theme.HelpKey != "" {
+		styles.ShortKey = styles.ShortKey.Foreground(lipgloss.Color(theme.HelpKey))
+		styles.FullKey = styles.FullKey.Foreground(lipgloss.Color(theme.HelpKey))
+	}
+	if theme.HelpDescription != "" {
+		styles.ShortDesc = styles.ShortDesc.Foreground(lipgloss.Color(theme.HelpDescription))
+		styles.FullDesc = styles.FullDesc.Foreground(lipgloss.Color(theme.HelpDescription))
+	}
+}
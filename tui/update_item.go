@@ -0,0 +1,187 @@
+package lazydynamo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const updateItemConfirmID = "update-item"
+
+// pendingUpdate is an attribute of the viewed row about to be set or removed,
+// and once previewed the update to apply
+type pendingUpdate struct {
+	row       string
+	attribute string
+	update    attributeUpdate
+}
+
+// attributeUpdate is a single-attribute change: value is set unless remove
+// is true
+type attributeUpdate struct {
+	attribute string
+	value     interface{}
+	remove    bool
+}
+
+// openSetAttribute asks for the new value of the selected attribute,
+// pre-filled with its current value as JSON
+func (m *MainModel) openSetAttribute() tea.Cmd {
+	attribute, ok := m.viewRowModel.selectedAttribute()
+	if !ok {
+		m.notice = "Press tab to select an attribute first"
+		return nil
+	}
+
+	current := ""
+	if obj, err := tools.ParseItemJSON(m.tableDataModel.selectedRow); err == nil {
+		// Values too long for the prompt aren't pre-filled, so they can't be
+		// saved truncated by accident
+		if data, err := json.Marshal(obj[attribute.name]); err == nil && len(data) <= m.prompt.CharLimit {
+			current = string(data)
+		}
+	}
+
+	m.pendingUpdate = &pendingUpdate{row: m.tableDataModel.selectedRow, attribute: attribute.name}
+	cmd := m.openPrompt(setAttributePrompt, fmt.Sprintf("Set %s to (JSON):", attribute.name))
+	m.prompt.SetValue(current)
+	return cmd
+}
+
+// setAttribute previews setting the pending attribute to the JSON value
+// entered
+func (m *MainModel) setAttribute(value string) tea.Cmd {
+	pending := m.pendingUpdate
+	m.pendingUpdate = nil
+	if pending == nil || !m.allowWrite() {
+		return nil
+	}
+
+	parsed, err := tools.ParseItemJSON(fmt.Sprintf(`{"value":%s}`, value))
+	if err != nil {
+		m.lastErr = fmt.Errorf("%s must be JSON, e.g. \"text\", 42 or true: %w", pending.attribute, err)
+		return nil
+	}
+
+	pending.update = attributeUpdate{attribute: pending.attribute, value: parsed["value"]}
+	m.pendingUpdate = pending
+	message := fmt.Sprintf("Set %s on this item?", pending.attribute)
+	return m.tableDataModel.previewUpdate(updateItemConfirmID, message, m.tableDataModel.selectedTable, pending.row, pending.update)
+}
+
+// confirmRemoveAttribute asks before removing the selected attribute
+func (m *MainModel) confirmRemoveAttribute() tea.Cmd {
+	attribute, ok := m.viewRowModel.selectedAttribute()
+	if !ok {
+		m.notice = "Press tab to select an attribute first"
+		return nil
+	}
+
+	m.pendingUpdate = &pendingUpdate{
+		row:       m.tableDataModel.selectedRow,
+		attribute: attribute.name,
+		update:    attributeUpdate{attribute: attribute.name, remove: true},
+	}
+	message := fmt.Sprintf("Remove %s from this item?", attribute.name)
+	return m.tableDataModel.previewUpdate(updateItemConfirmID, message, m.tableDataModel.selectedTable, m.tableDataModel.selectedRow, m.pendingUpdate.update)
+}
+
+// applyUpdate runs the pending update once its preview was confirmed
+func (m *MainModel) applyUpdate(confirmed bool) tea.Cmd {
+	pending := m.pendingUpdate
+	m.pendingUpdate = nil
+	if pending == nil || !confirmed || !m.allowWrite() {
+		return nil
+	}
+
+	m.tableDataModel.loading = true
+	return tea.Batch(m.tableDataModel.updateItem(m.tableDataModel.selectedTable, pending.row, pending.update), m.tableDataModel.loadingIndicator.Tick)
+}
+
+// updateItemInput builds an UpdateItem request that changes a single
+// attribute of the row and leaves the others untouched. Names go through
+// ExpressionAttributeNames so reserved words like "status" work, and the
+// condition keeps the update from creating an item that no longer exists.
+func updateItemInput(tableName, row string, tableInfo *types.TableDescription, update attributeUpdate) (*dynamodb.UpdateItemInput, error) {
+	for _, element := range tableInfo.KeySchema {
+		if *element.AttributeName == update.attribute {
+			return nil, fmt.Errorf("%s is part of the primary key and can't be updated — edit the item instead", update.attribute)
+		}
+	}
+
+	key, err := rowKey(row, tableInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: &tableName,
+		Key:       key,
+		ExpressionAttributeNames: map[string]string{
+			"#attr": update.attribute,
+			"#pk":   *tableInfo.KeySchema[0].AttributeName,
+		},
+		ConditionExpression: aws.String("attribute_exists(#pk)"),
+		ReturnValues:        types.ReturnValueAllNew,
+	}
+
+	if update.remove {
+		input.UpdateExpression = aws.String("REMOVE #attr")
+		return input, nil
+	}
+
+	item, err := tools.MapToDynamoItem(map[string]interface{}{update.attribute: update.value})
+	if err != nil {
+		return nil, err
+	}
+	input.UpdateExpression = aws.String("SET #attr = :value")
+	input.ExpressionAttributeValues = map[string]types.AttributeValue{":value": item[update.attribute]}
+	return input, nil
+}
+
+// updateItem applies the update to the row's item and returns the updated
+// row in its place
+func (m TableDataModel) updateItem(tableName, row string, update attributeUpdate) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			return ItemSaveFailedMsg{err}
+		}
+
+		input, err := updateItemInput(tableName, row, tableInfo, update)
+		if err != nil {
+			return ItemSaveFailedMsg{err}
+		}
+
+		output, err := m.client.UpdateItem(ctx, input)
+		if err != nil {
+			log.Printf("Failed to update item: %v", err)
+			return ItemSaveFailedMsg{err}
+		}
+
+		mapItem, err := tools.DynamoItemToRowMap(output.Attributes, tools.KeyAttributes(tableInfo))
+		if err != nil {
+			return ItemSaveFailedMsg{err}
+		}
+		updated, err := json.Marshal(mapItem)
+		if err != nil {
+			return ItemSaveFailedMsg{err}
+		}
+
+		invalidateTableDataCache(m.region, tableName)
+
+		return ItemSavedMsg{region: m.region, table: tableName, row: string(updated), replaces: row}
+	}
+}
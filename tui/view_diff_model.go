@@ -0,0 +1,137 @@
+package lazydynamo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	diffChangedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+)
+
+type ViewDiffKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Top    key.Binding
+	Bottom key.Binding
+	Close  key.Binding
+	Help   key.Binding
+	Quit   key.Binding
+}
+
+func (k ViewDiffKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Close, k.Help, k.Quit}
+}
+
+func (k ViewDiffKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom},
+		{k.Close},
+		{k.Help, k.Quit},
+	}
+}
+
+var viewDiffKeys = ViewDiffKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "scroll up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "scroll down"),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("g/home", "go to top"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("G/end", "go to bottom"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "close diff"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "all keys"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+type ViewDiffModel struct {
+	keys ViewDiffKeyMap
+}
+
+func (m ViewDiffModel) New() ViewDiffModel {
+	return ViewDiffModel{
+		keys: viewDiffKeys,
+	}
+}
+
+// openDiff compares the two marked rows and shows their differences in the
+// data pane
+func (m *MainModel) openDiff() {
+	rows := m.tableDataModel.markedRows()
+	if len(rows) != 2 {
+		m.notice = fmt.Sprintf("Mark exactly two rows to compare (space) — %d marked", len(rows))
+		return
+	}
+
+	a, err := tools.ParseItemJSON(rows[0])
+	if err != nil {
+		m.lastErr = err
+		return
+	}
+	b, err := tools.ParseItemJSON(rows[1])
+	if err != nil {
+		m.lastErr = err
+		return
+	}
+
+	m.diffViewport.SetContent(renderDiff(tools.DiffItems(a, b)))
+	m.diffViewport.GotoTop()
+	m.state = ViewingDiff
+}
+
+// renderDiff lists the differing attributes of two items, one per line:
+// "+ name: value" when only the second has it, "- name: value" when only the
+// first does and "~ name: old → new" when they disagree
+func renderDiff(diffs []tools.FieldDiff) string {
+	if len(diffs) == 0 {
+		return "The rows are identical"
+	}
+
+	lines := make([]string, len(diffs))
+	for i, diff := range diffs {
+		switch diff.Kind {
+		case tools.DiffAdded:
+			lines[i] = diffAddedStyle.Render(fmt.Sprintf("+ %s: %s", diff.Path, diffValue(diff.New)))
+		case tools.DiffRemoved:
+			lines[i] = diffRemovedStyle.Render(fmt.Sprintf("- %s: %s", diff.Path, diffValue(diff.Old)))
+		default:
+			lines[i] = diffChangedStyle.Render(fmt.Sprintf("~ %s: %s → %s", diff.Path, diffValue(diff.Old), diffValue(diff.New)))
+		}
+	}
+
+	return fmt.Sprintf("%d attributes differ (first marked row → second)\n\n", len(diffs)) + strings.Join(lines, "\n")
+}
+
+// diffValue writes a value of a diff as compact JSON
+func diffValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,124 @@
+package lazydynamo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type ViewErrorKeyMap struct {
+	Up    key.Binding
+	Down  key.Binding
+	Copy  key.Binding
+	Close key.Binding
+	Help  key.Binding
+	Quit  key.Binding
+}
+
+func (k ViewErrorKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Copy, k.Close, k.Help, k.Quit}
+}
+
+func (k ViewErrorKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down},
+		{k.Copy, k.Close},
+		{k.Help, k.Quit},
+	}
+}
+
+var viewErrorKeys = ViewErrorKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "scroll up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "scroll down"),
+	),
+	Copy: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy details"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "close details"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "all keys"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+type ViewErrorModel struct {
+	keys          ViewErrorKeyMap
+	details       string // the error shown, as plain text
+	previousState sessionState
+}
+
+func (m ViewErrorModel) New() ViewErrorModel {
+	return ViewErrorModel{
+		keys: viewErrorKeys,
+	}
+}
+
+// openErrorDetails shows the error of the banner or, once it was cleared,
+// the last failed fetch
+func (m *MainModel) openErrorDetails() {
+	err := m.lastErr
+	if err == nil {
+		err = m.lastFetchErr
+	}
+	if err == nil {
+		m.notice = "No error to show"
+		return
+	}
+
+	m.viewErrorModel.details = errorDetails(err)
+	m.viewErrorModel.previousState = m.state
+	m.errorViewport.SetContent(m.viewErrorModel.details)
+	m.errorViewport.GotoTop()
+	m.state = ViewingError
+}
+
+// errorDetails writes out an error with the metadata AWS attached to it:
+// error code, request ID and HTTP status, followed by the chain of wrapped
+// errors
+func errorDetails(err error) string {
+	var b strings.Builder
+	b.WriteString(err.Error() + "\n\n")
+
+	var operationErr *smithy.OperationError
+	if errors.As(err, &operationErr) {
+		fmt.Fprintf(&b, "Operation:   %s %s\n", operationErr.ServiceID, operationErr.OperationName)
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		fmt.Fprintf(&b, "Error code:  %s\n", apiErr.ErrorCode())
+		fmt.Fprintf(&b, "Message:     %s\n", apiErr.ErrorMessage())
+		fmt.Fprintf(&b, "Fault:       %s\n", apiErr.ErrorFault())
+	}
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) {
+		fmt.Fprintf(&b, "Request ID:  %s\n", responseErr.ServiceRequestID())
+	}
+	var httpErr *smithyhttp.ResponseError
+	if errors.As(err, &httpErr) {
+		fmt.Fprintf(&b, "HTTP status: %d\n", httpErr.HTTPStatusCode())
+	}
+
+	b.WriteString("\nWrapped errors:\n")
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		fmt.Fprintf(&b, "  %T: %s\n", e, e.Error())
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package lazydynamo
+
+import (
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	logRefreshInterval = 2 * time.Second
+	logTailBytes       = 64 * 1024
+)
+
+// LogTickMsg triggers a refresh of the log viewer. The generation drops ticks
+// scheduled before the viewer was last opened, so reopening it doesn't start
+// a second refresh loop.
+type LogTickMsg struct {
+	generation int
+}
+
+type ViewLogsKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Top    key.Binding
+	Bottom key.Binding
+	Close  key.Binding
+	Help   key.Binding
+	Quit   key.Binding
+}
+
+func (k ViewLogsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Close, k.Help, k.Quit}
+}
+
+func (k ViewLogsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom},
+		{k.Close},
+		{k.Help, k.Quit},
+	}
+}
+
+var viewLogsKeys = ViewLogsKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "scroll up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "scroll down"),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("g/home", "go to top"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("G/end", "go to bottom"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "ctrl+l"),
+		key.WithHelp("esc", "close logs"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "all keys"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+type ViewLogsModel struct {
+	keys          ViewLogsKeyMap
+	previousState sessionState
+	generation    int
+}
+
+func (m ViewLogsModel) New() ViewLogsModel {
+	return ViewLogsModel{
+		keys: viewLogsKeys,
+	}
+}
+
+func (m ViewLogsModel) tickLogs() tea.Cmd {
+	msg := LogTickMsg{generation: m.generation}
+	return tea.Tick(logRefreshInterval, func(time.Time) tea.Msg {
+		return msg
+	})
+}
+
+// refreshLogs loads the tail of the log file into the logs viewport, staying
+// pinned to the bottom if the user hadn't scrolled up
+func (m *MainModel) refreshLogs() {
+	content, err := tools.TailFile(LogFilePath, logTailBytes)
+	if err != nil {
+		content = "Could not read the log file: " + err.Error()
+	}
+
+	atBottom := m.logsViewport.AtBottom()
+	m.logsViewport.SetContent(content)
+	if atBottom {
+		m.logsViewport.GotoBottom()
+	}
+}
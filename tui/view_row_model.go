@@ -1,23 +1,80 @@
 package lazydynamo
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
+// TypedRowFetchedMsg carries a row re-read from DynamoDB with the type of each
+// attribute kept
+type TypedRowFetchedMsg struct {
+	row   string
+	typed string
+	err   error
+}
+
 type ViewRowKeyMap struct {
-	Up   key.Binding
-	Down key.Binding
-	Help key.Binding
-	Quit key.Binding
+	Up           key.Binding
+	Down         key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	ToggleTypes  key.Binding
+	Minify       key.Binding
+	RenderFull   key.Binding
+	TreeView     key.Binding
+	ExpandJSON   key.Binding
+	Expand       key.Binding
+	Collapse     key.Binding
+	Fold         key.Binding
+	ExtractPath  key.Binding
+	NextAttr     key.Binding
+	PrevAttr     key.Binding
+	CopyValue    key.Binding
+	CopyRow      key.Binding
+	CopyStruct   key.Binding
+	FollowRef    key.Binding
+	ExternalEdit key.Binding
+	SetAttr      key.Binding
+	RemoveAttr   key.Binding
+	Help         key.Binding
+	Quit         key.Binding
 }
 
 func (k ViewRowKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help, k.Quit}
+	return []key.Binding{k.ToggleTypes, k.RenderFull, k.Help, k.Quit}
+}
+
+// disableWrites marks the bindings of write actions as disabled in the help
+func (k *ViewRowKeyMap) disableWrites() {
+	for _, binding := range []*key.Binding{&k.ExternalEdit, &k.SetAttr, &k.RemoveAttr} {
+		help := binding.Help()
+		binding.SetHelp(help.Key, help.Desc+" (read-only)")
+	}
 }
 
 func (k ViewRowKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down},
+		{k.Up, k.Down, k.Top, k.Bottom},
+		{k.PageUp, k.PageDown, k.HalfPageUp, k.HalfPageDown},
+		{k.ToggleTypes, k.Minify, k.RenderFull, k.ExtractPath, k.ExpandJSON},
+		{k.TreeView, k.Expand, k.Collapse, k.Fold},
+		{k.NextAttr, k.PrevAttr, k.CopyValue, k.CopyRow, k.CopyStruct, k.FollowRef},
+		{k.ExternalEdit, k.SetAttr, k.RemoveAttr},
 		{k.Help, k.Quit},
 	}
 }
@@ -31,9 +88,106 @@ var viewRowKeys = ViewRowKeyMap{
 		key.WithKeys("down", "j"),
 		key.WithHelp("↓/j", "move down"),
 	),
+	Top: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("g/home", "go to top"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("G/end", "go to bottom"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup", "ctrl+b"),
+		key.WithHelp("pgup/ctrl+b", "page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown", "ctrl+f"),
+		key.WithHelp("pgdn/ctrl+f", "page down"),
+	),
+	HalfPageUp: key.NewBinding(
+		key.WithKeys("ctrl+u"),
+		key.WithHelp("ctrl+u", "half page up"),
+	),
+	HalfPageDown: key.NewBinding(
+		key.WithKeys("ctrl+d"),
+		key.WithHelp("ctrl+d", "half page down"),
+	),
+	ToggleTypes: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "toggle raw AttributeValue JSON"),
+	),
+	Minify: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "toggle minified JSON"),
+	),
+	RenderFull: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "render large row in full"),
+		key.WithDisabled(),
+	),
+	TreeView: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "toggle tree view"),
+	),
+	ExpandJSON: key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "toggle expanding JSON in strings"),
+	),
+	Expand: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "expand (tree view)"),
+	),
+	Collapse: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "collapse (tree view)"),
+	),
+	Fold: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "fold/unfold (tree view)"),
+	),
+	ExtractPath: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "show a path only"),
+	),
+	NextAttr: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "next attribute"),
+	),
+	PrevAttr: key.NewBinding(
+		key.WithKeys("shift+tab"),
+		key.WithHelp("shift+tab", "previous attribute"),
+	),
+	CopyValue: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy attribute value"),
+	),
+	CopyRow: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy row JSON"),
+	),
+	CopyStruct: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "copy as Go struct"),
+	),
+	FollowRef: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open referenced item"),
+	),
+	ExternalEdit: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit in $EDITOR"),
+	),
+	SetAttr: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "update attribute value"),
+	),
+	RemoveAttr: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "remove attribute"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
-		key.WithHelp("?", "toggle help"),
+		key.WithHelp("?", "all keys"),
 	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "esc", "ctrl+c"),
@@ -41,12 +195,167 @@ var viewRowKeys = ViewRowKeyMap{
 	),
 }
 
+// largeRowSize is how large the JSON of a row may be before it is shown as
+// plain text, since highlighting it takes long enough to hang the UI
+const largeRowSize = 64 << 10
+
+// rowAttribute is one top-level attribute of the viewed row, with its value
+// in the form it is copied in
+type rowAttribute struct {
+	name  string
+	value string
+}
+
 type ViewRowModel struct {
 	keys ViewRowKeyMap
+
+	showTypes  bool
+	minified   bool              // show rows as compact JSON; kept across rows
+	treeView   bool              // show rows as a foldable tree; kept across rows
+	expandJSON bool              // show strings holding JSON as JSON; kept across rows
+	tree       *rowTree          // the viewed row parsed for the tree view
+	path       string            // dotted path the view is narrowed down to, if any
+	renderFull bool              // highlight the row even when it is large
+	typedRows  map[string]string // typed JSON of rows already fetched, by row
+
+	attributes []rowAttribute
+	selected   int // index into attributes, -1 when none is selected
 }
 
 func (m ViewRowModel) New() ViewRowModel {
 	return ViewRowModel{
-		keys: viewRowKeys,
+		keys:      viewRowKeys,
+		typedRows: make(map[string]string),
+	}
+}
+
+// setRow prepares attribute selection for a newly opened row
+func (m *ViewRowModel) setRow(row string) {
+	m.selected = -1
+	m.attributes = nil
+
+	obj, err := tools.ParseItemJSON(row)
+	if err != nil {
+		return
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, ok := obj[name].(string)
+		if !ok {
+			data, err := json.Marshal(obj[name])
+			if err != nil {
+				continue
+			}
+			value = string(data)
+		}
+		m.attributes = append(m.attributes, rowAttribute{name: name, value: value})
+	}
+}
+
+// goStruct generates a Go struct for the shape of row, named after the table.
+// The typed row is used when it was fetched, as the plain JSON loses sets and
+// binary values; typed reports whether it was.
+func (m ViewRowModel) goStruct(tableName, row string) (source string, typed bool, err error) {
+	if typedRow, ok := m.typedRows[row]; ok {
+		var item map[string]interface{}
+		if err := json.Unmarshal([]byte(typedRow), &item); err != nil {
+			return "", false, err
+		}
+		source, err := tools.GoStructFromTyped(tableName, item)
+		return source, true, err
+	}
+
+	source, err = tools.GoStructFromJSON(tableName, row)
+	return source, false, err
+}
+
+// moveSelection selects the next (delta 1) or previous (delta -1) attribute,
+// wrapping around at either end
+func (m *ViewRowModel) moveSelection(delta int) (rowAttribute, bool) {
+	if len(m.attributes) == 0 {
+		return rowAttribute{}, false
+	}
+
+	if m.selected < 0 && delta < 0 {
+		m.selected = 0
+	}
+	m.selected = (m.selected + delta + len(m.attributes)) % len(m.attributes)
+	return m.attributes[m.selected], true
+}
+
+// selectedAttribute returns the attribute currently selected, if any
+func (m ViewRowModel) selectedAttribute() (rowAttribute, bool) {
+	if m.selected < 0 || m.selected >= len(m.attributes) {
+		return rowAttribute{}, false
+	}
+	return m.attributes[m.selected], true
+}
+
+// fetchTypedRow reads the row back from DynamoDB, since the rows in the list
+// no longer know the DynamoDB type of their attributes
+func (m TableDataModel) fetchTypedRow(tableName, row string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			log.Printf("Failed to describe table: %v", err)
+			return TypedRowFetchedMsg{row: row, err: err}
+		}
+
+		key, err := rowKey(row, tableInfo)
+		if err != nil {
+			return TypedRowFetchedMsg{row: row, err: err}
+		}
+
+		output, err := m.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: &tableName,
+			Key:       key,
+		})
+		if err != nil {
+			return TypedRowFetchedMsg{row: row, err: err}
+		}
+		if output.Item == nil {
+			return TypedRowFetchedMsg{row: row, err: fmt.Errorf("item no longer exists in %s", tableName)}
+		}
+
+		typed, err := tools.DynamoItemToTypedMap(output.Item)
+		if err != nil {
+			return TypedRowFetchedMsg{row: row, err: err}
+		}
+
+		data, err := json.Marshal(typed)
+		if err != nil {
+			return TypedRowFetchedMsg{row: row, err: err}
+		}
+
+		return TypedRowFetchedMsg{row: row, typed: string(data)}
+	}
+}
+
+// plainLargeRow indents a large row without highlighting it and cuts it at
+// the last line that fits in largeRowSize, under a line saying how to render
+// it in full
+func plainLargeRow(rawJSON string) string {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(rawJSON), "", "  "); err != nil {
+		return "Could not render row."
+	}
+
+	shown, text := "shown", indented.String()
+	if len(text) > largeRowSize {
+		text = text[:largeRowSize]
+		if i := strings.LastIndexByte(text, '\n'); i > 0 {
+			text = text[:i]
+		}
+		shown, text = "cut short", text+"\n…"
 	}
+	return fmt.Sprintf("Row is %s, %s without highlighting — press f to render it in full\n\n%s", formatBytes(int64(len(rawJSON))), shown, text)
 }
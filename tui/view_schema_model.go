@@ -0,0 +1,172 @@
+package lazydynamo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// schemaSampleSize is how many of the loaded rows the schema is inferred from
+const schemaSampleSize = 1000
+
+type ViewSchemaKeyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Top     key.Binding
+	Bottom  key.Binding
+	Summary key.Binding
+	Close   key.Binding
+	Help    key.Binding
+	Quit    key.Binding
+}
+
+func (k ViewSchemaKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Close, k.Help, k.Quit}
+}
+
+func (k ViewSchemaKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom},
+		{k.Summary, k.Close},
+		{k.Help, k.Quit},
+	}
+}
+
+var viewSchemaKeys = ViewSchemaKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "scroll up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "scroll down"),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("g/home", "go to top"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("G/end", "go to bottom"),
+	),
+	Summary: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "toggle value summary"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "close schema"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "all keys"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+// summaryValueWidth is how wide a value in the summary may be before it is cut
+const summaryValueWidth = 40
+
+type ViewSchemaModel struct {
+	keys ViewSchemaKeyMap
+
+	summary bool // show the value summary instead of the schema; kept across tables
+}
+
+func (m ViewSchemaModel) New() ViewSchemaModel {
+	return ViewSchemaModel{
+		keys: viewSchemaKeys,
+	}
+}
+
+// openSchema infers the schema of the selected table from a sample of the
+// loaded rows, or summarizes their values, and shows it in the data pane
+func (m *MainModel) openSchema() {
+	rows := listItemsToRows(m.tableDataModel.dataList.Items())
+	total := len(rows)
+	if len(rows) > schemaSampleSize {
+		rows = rows[:schemaSampleSize]
+	}
+
+	if m.viewSchemaModel.summary {
+		m.schemaViewport.SetContent(renderSummary(tools.SummarizeAttributes(rows), len(rows), total))
+	} else {
+		m.schemaViewport.SetContent(renderSchema(tools.InferSchema(rows), total))
+	}
+	m.schemaViewport.GotoTop()
+	m.state = ViewingSchema
+}
+
+// attributeNames lists the top-level attributes of a sample of the loaded
+// rows, the most common first
+func (m TableDataModel) attributeNames() []string {
+	rows := listItemsToRows(m.dataList.Items())
+	if len(rows) > schemaSampleSize {
+		rows = rows[:schemaSampleSize]
+	}
+
+	schema := tools.InferSchema(rows)
+	names := make([]string, len(schema.Attributes))
+	for i, attribute := range schema.Attributes {
+		names[i] = attribute.Name
+	}
+	return names
+}
+
+// renderSchema lists each attribute with its types and how many of the
+// sampled items have it, e.g. "email  S (98%)"
+func renderSchema(schema tools.Schema, total int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Inferred from %s of %s loaded items\n\n", formatCount(schema.Items), formatCount(total))
+
+	nameWidth := 0
+	for _, attribute := range schema.Attributes {
+		nameWidth = max(nameWidth, lipgloss.Width(attribute.Name))
+	}
+
+	for _, attribute := range schema.Attributes {
+		fmt.Fprintf(&b, "%-*s  %s (%.0f%%)\n", nameWidth, attribute.Name, strings.Join(attribute.TypeNames(), "|"), attribute.Presence(schema.Items)*100)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderSummary lists each attribute with how many distinct values it has,
+// the range of numeric ones and the most common values, e.g.
+//
+//	status  3 distinct in 1,000
+//	  "active" ×812 "closed" ×150 "pending" ×38
+func renderSummary(summaries []tools.AttrSummary, sampled, total int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summarized from %s of %s loaded items\n\n", formatCount(sampled), formatCount(total))
+
+	nameWidth := 0
+	for _, summary := range summaries {
+		nameWidth = max(nameWidth, lipgloss.Width(summary.Name))
+	}
+
+	for _, summary := range summaries {
+		fmt.Fprintf(&b, "%-*s  %s distinct in %s", nameWidth, summary.Name, formatCount(summary.Distinct), formatCount(summary.Count))
+		if summary.Numeric {
+			fmt.Fprintf(&b, ", %s to %s",
+				strconv.FormatFloat(summary.Min, 'g', -1, 64),
+				strconv.FormatFloat(summary.Max, 'g', -1, 64))
+		}
+		b.WriteString("\n ")
+
+		for _, top := range summary.Top {
+			value, _ := truncateToWidth(top.Value, summaryValueWidth)
+			fmt.Fprintf(&b, " %s ×%s", value, formatCount(top.Count))
+		}
+		b.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,386 @@
+package lazydynamo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	streamtypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// streamPollInterval is how often the shards of a tailed stream are read
+	streamPollInterval = time.Second
+	// maxStreamRecords is how many records the stream view keeps, dropping
+	// the oldest ones
+	maxStreamRecords = 500
+)
+
+// StreamShardsMsg carries the shard iterators a stream is tailed with, after
+// it was opened or its shards were looked up again
+type StreamShardsMsg struct {
+	generation int
+	iterators  map[string]string // next iterator of every open shard, by shard ID
+	known      map[string]bool   // every shard seen, read or not
+	viewType   string
+	err        error
+}
+
+// StreamRecordsMsg carries the records read from a stream's shards in one poll
+type StreamRecordsMsg struct {
+	generation int
+	records    []string
+	iterators  map[string]string
+	closed     bool // a shard ended, so its children have to be looked up
+	err        error
+}
+
+// StreamTickMsg triggers the next poll of the tailed stream
+type StreamTickMsg struct {
+	generation int
+}
+
+type ViewStreamKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Top    key.Binding
+	Bottom key.Binding
+	Clear  key.Binding
+	Close  key.Binding
+	Help   key.Binding
+	Quit   key.Binding
+}
+
+func (k ViewStreamKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Clear, k.Close, k.Help, k.Quit}
+}
+
+func (k ViewStreamKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom},
+		{k.Clear, k.Close},
+		{k.Help, k.Quit},
+	}
+}
+
+var viewStreamKeys = ViewStreamKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "scroll up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "scroll down"),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("g/home", "go to top"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("G/end", "go to bottom"),
+	),
+	Clear: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "clear records"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "stop tailing"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "all keys"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+// ViewStreamModel tails the DynamoDB stream of a table, listing its INSERT,
+// MODIFY and REMOVE records as they happen
+type ViewStreamModel struct {
+	keys          ViewStreamKeyMap
+	previousState sessionState
+	generation    int // drops polls of a stream tailed before
+
+	client    *dynamodbstreams.Client
+	table     string
+	arn       string
+	viewType  string
+	iterators map[string]string
+	known     map[string]bool
+	records   []string
+}
+
+func (m ViewStreamModel) New() ViewStreamModel {
+	return ViewStreamModel{
+		keys: viewStreamKeys,
+	}
+}
+
+// newStreamsClient creates a DynamoDB Streams client for region, pointed at
+// the same custom endpoint as DynamoDB unless Streams has its own
+func newStreamsClient(cfg aws.Config, region string) *dynamodbstreams.Client {
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_DYNAMODB_STREAMS")
+	if endpoint == "" {
+		endpoint = dynamoEndpoint()
+	}
+	return dynamodbstreams.NewFromConfig(cfg, func(o *dynamodbstreams.Options) {
+		o.Region = region
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+}
+
+// streamArn returns the ARN of the selected table's stream, or "" when the
+// table has none or hasn't been described yet
+func (m TableDataModel) streamArn() string {
+	tableInfo, ok := m.tableInfo.get(m.selectedTable)
+	if !ok || tableInfo.StreamSpecification == nil || !aws.ToBool(tableInfo.StreamSpecification.StreamEnabled) {
+		return ""
+	}
+	return aws.ToString(tableInfo.LatestStreamArn)
+}
+
+// updateStreamKey enables tailing the stream only for tables that have one
+func (m *TableDataModel) updateStreamKey() {
+	m.keys.Stream.SetEnabled(m.streamArn() != "")
+}
+
+// openStream starts tailing the selected table's stream from its latest
+// records
+func (m *MainModel) openStream(arn string) tea.Cmd {
+	m.viewStreamModel.previousState = m.state
+	m.viewStreamModel.generation++
+	m.viewStreamModel.client = newStreamsClient(m.awsConfig, m.tableDataModel.region)
+	m.viewStreamModel.table = m.tableDataModel.selectedTable
+	m.viewStreamModel.arn = arn
+	m.viewStreamModel.iterators = nil
+	m.viewStreamModel.known = nil
+	m.viewStreamModel.records = nil
+	m.state = ViewingStream
+	m.refreshStream()
+
+	return m.viewStreamModel.findShards(streamtypes.ShardIteratorTypeLatest)
+}
+
+// findShards describes the stream and gets an iterator for every open shard
+// not seen before. Shards found when the stream is opened are read from their
+// latest record; shards found later are children of ended ones and are read
+// from their start, so no change is missed.
+func (m ViewStreamModel) findShards(iteratorType streamtypes.ShardIteratorType) tea.Cmd {
+	client, arn, generation := m.client, m.arn, m.generation
+	iterators := make(map[string]string, len(m.iterators))
+	for shard, iterator := range m.iterators {
+		iterators[shard] = iterator
+	}
+	known := make(map[string]bool, len(m.known))
+	for shard := range m.known {
+		known[shard] = true
+	}
+
+	return trackWork(func() tea.Msg {
+		ctx, cancel := context.WithTimeout(appContext, 30*time.Second)
+		defer cancel()
+
+		msg := StreamShardsMsg{generation: generation, iterators: iterators, known: known}
+		var startShard *string
+		for {
+			output, err := client.DescribeStream(ctx, &dynamodbstreams.DescribeStreamInput{
+				StreamArn:             aws.String(arn),
+				ExclusiveStartShardId: startShard,
+			})
+			if err != nil {
+				msg.err = err
+				return msg
+			}
+			description := output.StreamDescription
+			msg.viewType = string(description.StreamViewType)
+
+			for _, shard := range description.Shards {
+				id := aws.ToString(shard.ShardId)
+				if known[id] {
+					continue
+				}
+				known[id] = true
+				ended := shard.SequenceNumberRange != nil && shard.SequenceNumberRange.EndingSequenceNumber != nil
+				if ended && iteratorType == streamtypes.ShardIteratorTypeLatest {
+					// Holds only changes made before the stream was opened
+					continue
+				}
+
+				iterator, err := client.GetShardIterator(ctx, &dynamodbstreams.GetShardIteratorInput{
+					StreamArn:         aws.String(arn),
+					ShardId:           shard.ShardId,
+					ShardIteratorType: iteratorType,
+				})
+				if err != nil {
+					msg.err = err
+					return msg
+				}
+				iterators[id] = aws.ToString(iterator.ShardIterator)
+			}
+
+			if description.LastEvaluatedShardId == nil {
+				return msg
+			}
+			startShard = description.LastEvaluatedShardId
+		}
+	})
+}
+
+func (m ViewStreamModel) tickStream() tea.Cmd {
+	msg := StreamTickMsg{generation: m.generation}
+	return tea.Tick(streamPollInterval, func(time.Time) tea.Msg {
+		return msg
+	})
+}
+
+// pollStream reads the records added to every open shard since the last poll
+func (m ViewStreamModel) pollStream() tea.Cmd {
+	client, generation := m.client, m.generation
+	iterators := make(map[string]string, len(m.iterators))
+	for shard, iterator := range m.iterators {
+		iterators[shard] = iterator
+	}
+
+	return trackWork(func() tea.Msg {
+		ctx, cancel := context.WithTimeout(appContext, 30*time.Second)
+		defer cancel()
+
+		msg := StreamRecordsMsg{generation: generation, iterators: iterators}
+		for shard, iterator := range iterators {
+			output, err := client.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{
+				ShardIterator: aws.String(iterator),
+			})
+			if err != nil {
+				msg.err = err
+				return msg
+			}
+
+			for _, record := range output.Records {
+				msg.records = append(msg.records, formatStreamRecord(record))
+			}
+			if output.NextShardIterator == nil {
+				delete(iterators, shard)
+				msg.closed = true
+			} else {
+				iterators[shard] = aws.ToString(output.NextShardIterator)
+			}
+		}
+		return msg
+	})
+}
+
+// formatStreamRecord writes a stream record as its event and key, followed by
+// the item images the stream holds
+func formatStreamRecord(record streamtypes.Record) string {
+	change := record.Dynamodb
+	if change == nil {
+		return string(record.EventName)
+	}
+
+	keyNames := make(map[string]bool, len(change.Keys))
+	for name := range change.Keys {
+		keyNames[name] = true
+	}
+	image := func(image map[string]streamtypes.AttributeValue) string {
+		row, err := tools.DynamoItemToRowMap(tools.StreamImageToItem(image), keyNames)
+		if err != nil {
+			return "(" + err.Error() + ")"
+		}
+		data, err := json.Marshal(row)
+		if err != nil {
+			return "(" + err.Error() + ")"
+		}
+		return string(data)
+	}
+
+	when := "--:--:--"
+	if change.ApproximateCreationDateTime != nil {
+		when = change.ApproximateCreationDateTime.Local().Format(time.TimeOnly)
+	}
+	lines := []string{fmt.Sprintf("%s  %-6s  %s", when, record.EventName, image(change.Keys))}
+	if change.OldImage != nil {
+		lines = append(lines, "    old  "+image(change.OldImage))
+	}
+	if change.NewImage != nil {
+		lines = append(lines, "    new  "+image(change.NewImage))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// handleStreamShards starts or keeps polling with the shards found
+func (m *MainModel) handleStreamShards(msg StreamShardsMsg) tea.Cmd {
+	if m.state != ViewingStream || msg.generation != m.viewStreamModel.generation {
+		return nil
+	}
+	if msg.err != nil {
+		log.Printf("Failed to read the shards of %s: %v", m.viewStreamModel.arn, msg.err)
+		m.lastErr = friendlyAWSError(msg.err, m.profile)
+		return nil
+	}
+
+	opened := m.viewStreamModel.known == nil
+	m.viewStreamModel.iterators = msg.iterators
+	m.viewStreamModel.known = msg.known
+	m.viewStreamModel.viewType = msg.viewType
+	if opened {
+		m.notice = fmt.Sprintf("Tailing the stream of %s (%s) — changes appear as they happen", m.viewStreamModel.table, msg.viewType)
+	}
+	m.refreshStream()
+	return m.viewStreamModel.tickStream()
+}
+
+// handleStreamRecords shows the records of a poll and schedules the next one,
+// looking up the children of shards that ended first
+func (m *MainModel) handleStreamRecords(msg StreamRecordsMsg) tea.Cmd {
+	if m.state != ViewingStream || msg.generation != m.viewStreamModel.generation {
+		return nil
+	}
+	if msg.err != nil {
+		log.Printf("Failed to read the records of %s: %v", m.viewStreamModel.arn, msg.err)
+		m.lastErr = friendlyAWSError(msg.err, m.profile)
+		m.notice = "Stopped tailing the stream — esc, then W to start again"
+		return nil
+	}
+
+	m.viewStreamModel.iterators = msg.iterators
+	if len(msg.records) > 0 {
+		records := append(m.viewStreamModel.records, msg.records...)
+		m.viewStreamModel.records = records[max(0, len(records)-maxStreamRecords):]
+		m.refreshStream()
+	}
+	if msg.closed || len(msg.iterators) == 0 {
+		return m.viewStreamModel.findShards(streamtypes.ShardIteratorTypeTrimHorizon)
+	}
+	return m.viewStreamModel.tickStream()
+}
+
+// refreshStream shows the records read so far, staying pinned to the newest
+// one unless the user scrolled up
+func (m *MainModel) refreshStream() {
+	content := "Waiting for changes to " + m.viewStreamModel.table + "…"
+	if len(m.viewStreamModel.records) > 0 {
+		content = strings.Join(m.viewStreamModel.records, "\n")
+	}
+
+	atBottom := m.streamViewport.AtBottom()
+	m.streamViewport.SetContent(lipgloss.NewStyle().Width(m.streamViewport.Width).Render(content))
+	if atBottom {
+		m.streamViewport.GotoBottom()
+	}
+}
@@ -0,0 +1,73 @@
+package lazydynamo
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// wrapRowLines is how many lines a data row takes while rows are wrapped. The
+// list gives every item the same height, so short rows are padded.
+const wrapRowLines = 3
+
+// toggleWrap switches the data rows between one truncated line and
+// wrapRowLines wrapped lines
+func (m *TableDataModel) toggleWrap() {
+	m.wrapRows = !m.wrapRows
+	m.refreshDelegate()
+}
+
+// refreshDelegate redraws the data rows with the current scroll offset, marks
+// and wrapping
+func (m *TableDataModel) refreshDelegate() {
+	delegate := tableDataDelegate{offset: m.hOffset, marked: m.marked}
+	if m.wrapRows {
+		delegate.lines = wrapRowLines
+	}
+	m.dataList.SetDelegate(delegate)
+}
+
+// renderWrapped draws a row over d.lines lines of maxWidth cells, cutting the
+// last one with an ellipsis if the row doesn't fit. str is the row from rune
+// offset on.
+func (d tableDataDelegate) renderWrapped(w io.Writer, m list.Model, index int, str string, offset, maxWidth int, marker string) {
+	style, prefix := itemStyle, ""
+	if index == m.Index() {
+		style, prefix = selectedItemStyle, "> "
+	}
+	indent := strings.Repeat(" ", len(prefix)+len(marker))
+
+	lines := make([]string, d.lines)
+	start := 0
+	for n := range lines {
+		if str == "" {
+			break
+		}
+
+		var line string
+		var kept int
+		if n == len(lines)-1 {
+			line, kept = truncateToWidth(str, max(maxWidth, 1))
+		} else {
+			line = ansi.Truncate(str, max(maxWidth, 1), "")
+			kept = utf8.RuneCountInString(line)
+		}
+
+		lines[n] = highlightMatches(m, index, line, offset+start, kept, style)
+		str = string([]rune(str)[kept:])
+		start += kept
+	}
+
+	for n, line := range lines {
+		lead := indent
+		if n == 0 {
+			lead = prefix + marker
+		}
+		lines[n] = style.Render(lead + line)
+	}
+	fmt.Fprint(w, strings.Join(lines, "\n"))
+}
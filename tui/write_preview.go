@@ -0,0 +1,191 @@
+package lazydynamo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/TheChessDev/lazydynamo/internals/components"
+	"github.com/TheChessDev/lazydynamo/internals/tools"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	putItemConfirmID = "put-item"
+
+	// maxPreviewDeletes bounds how many keys a batch delete preview lists
+	maxPreviewDeletes = 50
+)
+
+// WritePreviewMsg carries the preview of a write, ready to be confirmed
+type WritePreviewMsg struct {
+	id       string
+	message  string
+	preview  string
+	expected string // phrase to type to confirm, for the most destructive writes
+	err      error
+}
+
+// writeRequestPreview is what a write will send to DynamoDB, with attribute
+// values in their typed form
+type writeRequestPreview struct {
+	Operation                 string                   `json:"operation"`
+	TableName                 string                   `json:"tableName"`
+	Key                       map[string]interface{}   `json:"key,omitempty"`
+	Item                      map[string]interface{}   `json:"item,omitempty"`
+	Keys                      []map[string]interface{} `json:"keys,omitempty"`
+	UpdateExpression          string                   `json:"updateExpression,omitempty"`
+	ConditionExpression       string                   `json:"conditionExpression,omitempty"`
+	ExpressionAttributeNames  map[string]string        `json:"expressionAttributeNames,omitempty"`
+	ExpressionAttributeValues map[string]interface{}   `json:"expressionAttributeValues,omitempty"`
+	More                      int                      `json:"more,omitempty"` // keys left out of the preview
+}
+
+func (p writeRequestPreview) String() string {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("could not render preview: %v", err)
+	}
+	return string(data)
+}
+
+// putItemPreview previews the PutItem of obj replacing row, if any. The
+// table's description tells whether the put creates a new item; without it
+// the condition putItem adds for new items can't be shown.
+func putItemPreview(tableName string, obj map[string]interface{}, row string, tableInfo *types.TableDescription) (string, error) {
+	item, err := tools.MapToDynamoItem(obj)
+	if err != nil {
+		return "", err
+	}
+
+	isNew := row == ""
+	if !isNew && tableInfo != nil {
+		if data, err := json.Marshal(obj); err == nil {
+			isNew = !sameKey(tableInfo, row, string(data))
+		}
+	}
+	input := putItemInput(tableName, item, tableInfo, isNew)
+
+	typed, err := tools.DynamoItemToTypedMap(input.Item)
+	if err != nil {
+		return "", err
+	}
+
+	return writeRequestPreview{
+		Operation:                "PutItem",
+		TableName:                tableName,
+		Item:                     typed,
+		ConditionExpression:      aws.ToString(input.ConditionExpression),
+		ExpressionAttributeNames: input.ExpressionAttributeNames,
+	}.String(), nil
+}
+
+// confirmPut asks before putting obj, showing the request that will be sent.
+// row is the data row the item replaces, if any.
+func (m *MainModel) confirmPut(row string, obj map[string]interface{}, message string) {
+	if row != "" && !m.allowRowWrite() {
+		return
+	}
+
+	tableInfo, _ := m.tableDataModel.tableInfo.get(m.tableDataModel.selectedTable)
+	preview, err := putItemPreview(m.tableDataModel.selectedTable, obj, row, tableInfo)
+	if err != nil {
+		m.lastErr = err
+		return
+	}
+
+	m.pendingEdit = &pendingEdit{row: row, obj: obj}
+	m.confirmDialog = m.confirmDialog.OpenWithDetail(putItemConfirmID, message, preview)
+}
+
+// showWritePreview opens the confirmation of a previewed write, or drops the
+// pending write when the preview failed
+func (m *MainModel) showWritePreview(msg WritePreviewMsg) tea.Cmd {
+	if msg.err != nil {
+		m.lastErr = msg.err
+		return m.handleConfirmation(components.ConfirmResultMsg{ID: msg.id})
+	}
+
+	if msg.expected != "" {
+		m.confirmDialog = m.confirmDialog.OpenTyped(msg.id, msg.message, msg.expected).WithDetail(msg.preview)
+		return nil
+	}
+	m.confirmDialog = m.confirmDialog.OpenWithDetail(msg.id, msg.message, msg.preview)
+	return nil
+}
+
+// previewUpdate builds the UpdateItem request for the update and previews it
+func (m TableDataModel) previewUpdate(id, message, tableName, row string, update attributeUpdate) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			return WritePreviewMsg{id: id, err: err}
+		}
+
+		input, err := updateItemInput(tableName, row, tableInfo, update)
+		if err != nil {
+			return WritePreviewMsg{id: id, err: err}
+		}
+
+		key, err := tools.DynamoItemToTypedMap(input.Key)
+		if err != nil {
+			return WritePreviewMsg{id: id, err: err}
+		}
+		values, err := tools.DynamoItemToTypedMap(input.ExpressionAttributeValues)
+		if err != nil {
+			return WritePreviewMsg{id: id, err: err}
+		}
+
+		preview := writeRequestPreview{
+			Operation:                 "UpdateItem",
+			TableName:                 tableName,
+			Key:                       key,
+			UpdateExpression:          aws.ToString(input.UpdateExpression),
+			ConditionExpression:       aws.ToString(input.ConditionExpression),
+			ExpressionAttributeNames:  input.ExpressionAttributeNames,
+			ExpressionAttributeValues: values,
+		}
+
+		return WritePreviewMsg{id: id, message: message, preview: preview.String()}
+	}
+}
+
+// previewDelete lists the keys a batch delete of the rows will remove. The
+// delete is confirmed by typing the table name.
+func (m TableDataModel) previewDelete(id, message, tableName string, rows []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		tableInfo, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			return WritePreviewMsg{id: id, err: err}
+		}
+
+		preview := writeRequestPreview{Operation: "BatchWriteItem (DeleteRequest)", TableName: tableName}
+		for i, row := range rows {
+			if i == maxPreviewDeletes {
+				preview.More = len(rows) - i
+				break
+			}
+			key, err := rowKey(row, tableInfo)
+			if err != nil {
+				continue
+			}
+			typed, err := tools.DynamoItemToTypedMap(key)
+			if err != nil {
+				continue
+			}
+			preview.Keys = append(preview.Keys, typed)
+		}
+
+		return WritePreviewMsg{id: id, message: message, preview: preview.String(), expected: tableName}
+	}
+}